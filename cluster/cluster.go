@@ -0,0 +1,97 @@
+package cluster
+
+import (
+	"fmt"
+	"math"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/itsmeashim/rdb/models"
+)
+
+var digits = regexp.MustCompile(`[0-9]+`)
+
+// Cluster is a group of records with the same response signature
+type Cluster struct {
+	Signature      string             `json:"signature"`
+	Size           int                `json:"size"`
+	Representative models.HTTPXData   `json:"representative"`
+	Members        []models.HTTPXData `json:"members,omitempty"`
+}
+
+// Signature describes the shape of a response: status, webserver and a
+// normalized title must match exactly, while words, lines and content length
+// are bucketed so values within tolerance (e.g. 0.1 for 10%) usually share a
+// bucket.
+func Signature(d models.HTTPXData, tolerance float64) string {
+	return fmt.Sprintf("%d|%s|%s|%d|%d|%d",
+		d.StatusCode, strings.ToLower(d.Webserver), normalizeTitle(d),
+		bucket(d.Words, tolerance), bucket(d.Lines, tolerance), bucket(d.ContentLength, tolerance))
+}
+
+// normalizeTitle lowercases the title and replaces the record's own hostname,
+// root domain and any numbers, so parked pages like "example.com is for
+// sale" cluster together.
+func normalizeTitle(d models.HTTPXData) string {
+	title := strings.Join(strings.Fields(strings.ToLower(d.Title)), " ")
+	for _, name := range []string{d.Hostname, d.RootDomain} {
+		if name != "" {
+			title = strings.ReplaceAll(title, strings.ToLower(name), "{host}")
+		}
+	}
+	return digits.ReplaceAllString(title, "0")
+}
+
+// bucket maps n onto a logarithmic scale with steps of (1 + tolerance).
+func bucket(n int, tolerance float64) int {
+	if tolerance <= 0 || n <= 0 {
+		return n
+	}
+	return int(math.Floor(math.Log(float64(n)) / math.Log(1+tolerance)))
+}
+
+// Group clusters records by signature, keeping their order within each
+// cluster; the first member is the representative. Clusters are sorted by
+// size, largest first.
+func Group(records []models.HTTPXData, tolerance float64) []Cluster {
+	index := map[string]int{}
+	var clusters []Cluster
+	for _, d := range records {
+		sig := Signature(d, tolerance)
+		i, ok := index[sig]
+		if !ok {
+			i = len(clusters)
+			index[sig] = i
+			clusters = append(clusters, Cluster{Signature: sig, Representative: d})
+		}
+		clusters[i].Size++
+		clusters[i].Members = append(clusters[i].Members, d)
+	}
+
+	sort.SliceStable(clusters, func(i, j int) bool { return clusters[i].Size > clusters[j].Size })
+	return clusters
+}
+
+// Collapse drops every record but the first of clusters with at least
+// minSize members, preserving the original order.
+func Collapse(records []models.HTTPXData, tolerance float64, minSize int) []models.HTTPXData {
+	sizes := map[string]int{}
+	for _, d := range records {
+		sizes[Signature(d, tolerance)]++
+	}
+
+	seen := map[string]bool{}
+	var out []models.HTTPXData
+	for _, d := range records {
+		sig := Signature(d, tolerance)
+		if sizes[sig] >= minSize {
+			if seen[sig] {
+				continue
+			}
+			seen[sig] = true
+		}
+		out = append(out, d)
+	}
+	return out
+}
@@ -0,0 +1,93 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/user"
+
+	"github.com/itsmeashim/rdb/config"
+	"github.com/itsmeashim/rdb/db"
+	"github.com/spf13/cobra"
+)
+
+var assignClear bool
+
+var assignCmd = &cobra.Command{
+	Use:   "assign <host|id|url> [user]",
+	Short: "Assign a host or URL to someone",
+	Long: `Record who is looking at a host, or at a single URL when given a record id
+or URL. "me" is the user from 'rdb config --user' (default: your login name).
+Assignments are kept across re-stores and can be listed with
+'rdb list --assignee <user>'.
+
+Examples:
+  rdb assign admin.example.com bob
+  rdb assign https://example.com/admin me
+  rdb assign admin.example.com --clear`,
+	Args: cobra.RangeArgs(1, 2),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		assignee := ""
+		switch {
+		case assignClear && len(args) == 2:
+			return fmt.Errorf("--clear does not take a user")
+		case !assignClear && len(args) == 1:
+			return fmt.Errorf("a user is required (or --clear)")
+		case !assignClear:
+			assignee = resolveUser(args[1])
+		}
+
+		cfg, err := config.Load()
+		if err != nil {
+			return fmt.Errorf("failed to load config: %w", err)
+		}
+
+		if err := db.Init(cfg); err != nil {
+			return err
+		}
+		defer db.Close()
+
+		ctx := context.Background()
+		targets, err := db.ResolveTargets(ctx, args[0], filterProgram)
+		if err != nil {
+			return err
+		}
+		if err := db.Assign(ctx, targets, assignee); err != nil {
+			return fmt.Errorf("failed to assign: %w", err)
+		}
+
+		for _, t := range targets {
+			if assignee == "" {
+				fmt.Printf("unassigned %s (%s)\n", t.Value, t.Program)
+			} else {
+				fmt.Printf("assigned %s (%s) to %s\n", t.Value, t.Program, assignee)
+			}
+		}
+		return nil
+	},
+}
+
+// resolveUser maps "me" to the configured user, or the login name when none
+// is configured.
+func resolveUser(name string) string {
+	if name != "me" {
+		return name
+	}
+	return currentUser()
+}
+
+func currentUser() string {
+	if cfg, err := config.Load(); err == nil && cfg.User != "" {
+		return cfg.User
+	}
+	if u, err := user.Current(); err == nil && u.Username != "" {
+		return u.Username
+	}
+	return os.Getenv("USER")
+}
+
+func init() {
+	assignCmd.Flags().StringVar(&filterProgram, "program", "", "Only assign the host within this program (default: every program it was seen in)")
+	assignCmd.Flags().BoolVar(&assignClear, "clear", false, "Remove the assignment")
+	rootCmd.AddCommand(assignCmd)
+}
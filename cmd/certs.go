@@ -0,0 +1,129 @@
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/itsmeashim/rdb/config"
+	"github.com/itsmeashim/rdb/db"
+	"github.com/spf13/cobra"
+)
+
+var (
+	certSAN            string
+	certIssuer         string
+	certExpiringWithin string
+	certSelfSigned     bool
+	certExpired        bool
+	certMismatchedHost bool
+	certCandidates     bool
+)
+
+var certsCmd = &cobra.Command{
+	Use:   "certs",
+	Short: "List stored TLS certificates",
+	Long: `List the TLS certificates captured with httpx -tls-grab, deduplicated by
+fingerprint, with the hosts that served them.
+
+Examples:
+  rdb certs --expiring-within 30d
+  rdb certs --issuer "Let's Encrypt" --san api.
+  rdb certs --self-signed --program myprogram
+  rdb certs --mismatched-host
+  rdb certs --candidates --program myprogram   # SAN names not yet probed`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		opts := db.CertOptions{
+			Program:        filterProgram,
+			SAN:            certSAN,
+			Issuer:         certIssuer,
+			SelfSigned:     certSelfSigned,
+			Expired:        certExpired,
+			MismatchedHost: certMismatchedHost,
+			Limit:          limit,
+		}
+		if certExpiringWithin != "" {
+			d, err := parseDuration(certExpiringWithin)
+			if err != nil {
+				return err
+			}
+			opts.ExpiringWithin = d
+		}
+
+		cfg, err := config.Load()
+		if err != nil {
+			return fmt.Errorf("failed to load config: %w", err)
+		}
+
+		if err := db.Init(cfg); err != nil {
+			return err
+		}
+		defer db.Close()
+
+		ctx := context.Background()
+
+		if certCandidates {
+			names, err := db.SANCandidates(ctx, filterProgram)
+			if err != nil {
+				return fmt.Errorf("failed to query certificate names: %w", err)
+			}
+			for _, n := range names {
+				fmt.Println(n)
+			}
+			return nil
+		}
+
+		results, err := db.ListCertificates(ctx, opts)
+		if err != nil {
+			return fmt.Errorf("failed to query certificates: %w", err)
+		}
+
+		if outputJSON {
+			encoder := json.NewEncoder(os.Stdout)
+			for _, r := range results {
+				encoder.Encode(r)
+			}
+			return nil
+		}
+
+		if len(results) == 0 {
+			fmt.Println("no certificates found")
+			return nil
+		}
+
+		w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+		for _, r := range results {
+			expires := "-"
+			if r.NotAfter != nil {
+				expires = r.NotAfter.Format(time.DateOnly)
+			}
+			flags := ""
+			if r.SelfSigned {
+				flags = "self-signed"
+			}
+			fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\t%s\t%s\n",
+				r.Fingerprint[:min(16, len(r.Fingerprint))], r.SubjectCN, truncate(strings.Join(r.SANs, ","), 40),
+				truncate(r.IssuerCN, 25), expires, flags, truncate(strings.Join(r.Hosts, ","), 50))
+		}
+		w.Flush()
+		return nil
+	},
+}
+
+func init() {
+	certsCmd.Flags().StringVar(&filterProgram, "program", "", "Only certificates served to this program")
+	certsCmd.Flags().StringVar(&certSAN, "san", "", "Filter by subject alternative name or CN (partial match)")
+	certsCmd.Flags().StringVar(&certIssuer, "issuer", "", "Filter by issuer CN, DN or organization (partial match)")
+	certsCmd.Flags().StringVar(&certExpiringWithin, "expiring-within", "", "Only certificates expiring within this duration (e.g., 30d)")
+	certsCmd.Flags().BoolVar(&certSelfSigned, "self-signed", false, "Only self-signed certificates")
+	certsCmd.Flags().BoolVar(&certExpired, "expired", false, "Only expired certificates")
+	certsCmd.Flags().BoolVar(&certMismatchedHost, "mismatched-host", false, "Only certificates served to hosts they do not cover")
+	certsCmd.Flags().BoolVar(&certCandidates, "candidates", false, "Print certificate names with no stored record, as subdomain candidates")
+	certsCmd.Flags().IntVarP(&limit, "limit", "n", 0, "Limit number of results (0 = all)")
+	certsCmd.Flags().BoolVarP(&outputJSON, "json", "j", false, "Output as JSON")
+	rootCmd.AddCommand(certsCmd)
+}
@@ -0,0 +1,109 @@
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"text/tabwriter"
+
+	"github.com/itsmeashim/rdb/cluster"
+	"github.com/itsmeashim/rdb/config"
+	"github.com/itsmeashim/rdb/db"
+	"github.com/spf13/cobra"
+)
+
+var (
+	clusterTolerance float64
+	clusterMinSize   int
+	clusterMembers   bool
+)
+
+var clusterCmd = &cobra.Command{
+	Use:   "cluster",
+	Short: "Group records with similar responses",
+	Long: `Group records by a similarity signature made from status code, webserver,
+normalized title, words, lines and content length, and print one
+representative per cluster with its size. Useful to collapse default pages,
+parked domains and catch-all responses.
+
+Words, lines and content length match when within --tolerance of each other
+(0.1 = roughly 10%).
+
+Examples:
+  rdb cluster --program myprogram
+  rdb cluster --min-size 10 --tolerance 0.05
+  rdb cluster --status 200 --json --members`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		cfg, err := config.Load()
+		if err != nil {
+			return fmt.Errorf("failed to load config: %w", err)
+		}
+
+		if err := db.Init(cfg); err != nil {
+			return err
+		}
+		defer db.Close()
+
+		opts, err := listOptions()
+		if err != nil {
+			return err
+		}
+		opts.Limit = 0
+
+		records, err := db.List(context.Background(), opts)
+		if err != nil {
+			return fmt.Errorf("failed to query data: %w", err)
+		}
+
+		clusters := cluster.Group(records, clusterTolerance)
+		shown := clusters[:0]
+		for _, c := range clusters {
+			if c.Size < clusterMinSize {
+				continue
+			}
+			if !clusterMembers {
+				c.Members = nil
+			}
+			shown = append(shown, c)
+		}
+		if limit > 0 && len(shown) > limit {
+			shown = shown[:limit]
+		}
+
+		if outputJSON {
+			encoder := json.NewEncoder(os.Stdout)
+			for _, c := range shown {
+				encoder.Encode(c)
+			}
+			return nil
+		}
+
+		if len(shown) == 0 {
+			fmt.Println("no clusters found")
+			return nil
+		}
+
+		w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+		for _, c := range shown {
+			r := c.Representative
+			fmt.Fprintf(w, "%d\t%d\t%s\t%s\t%d/%d/%d\t%s\n",
+				c.Size, r.StatusCode, r.Webserver, truncate(r.Title, 30), r.Words, r.Lines, r.ContentLength, r.URL)
+			for _, m := range c.Members {
+				fmt.Fprintf(w, "\t\t\t\t\t  %s\n", m.URL)
+			}
+		}
+		w.Flush()
+		return nil
+	},
+}
+
+func init() {
+	addFilterFlags(clusterCmd)
+	clusterCmd.Flags().Float64Var(&clusterTolerance, "tolerance", 0.1, "Relative tolerance for words, lines and content length (0 = exact)")
+	clusterCmd.Flags().IntVar(&clusterMinSize, "min-size", 2, "Only show clusters with at least this many records")
+	clusterCmd.Flags().BoolVar(&clusterMembers, "members", false, "List every member URL")
+	clusterCmd.Flags().IntVarP(&limit, "limit", "n", 0, "Limit number of clusters (0 = all)")
+	clusterCmd.Flags().BoolVarP(&outputJSON, "json", "j", false, "Output as JSON")
+	rootCmd.AddCommand(clusterCmd)
+}
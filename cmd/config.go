@@ -2,6 +2,8 @@ package cmd
 
 import (
 	"fmt"
+	"maps"
+	"slices"
 
 	"github.com/spf13/cobra"
 	"github.com/itsmeashim/rdb/config"
@@ -12,6 +14,11 @@ var (
 	maxConnections int
 	defaultProgram string
 	defaultPlatform string
+	maxBodySize     int
+	labelRules      string
+	userName        string
+	cloudRanges     []string
+	asnDB           string
 )
 
 var configCmd = &cobra.Command{
@@ -43,6 +50,36 @@ var configCmd = &cobra.Command{
 			changed = true
 		}
 
+		if cmd.Flags().Changed("max-body-size") {
+			cfg.MaxBodySize = maxBodySize
+			changed = true
+		}
+
+		if userName != "" {
+			cfg.User = userName
+			changed = true
+		}
+
+		if cmd.Flags().Changed("label-rules") {
+			cfg.LabelRules = labelRules
+			changed = true
+		}
+
+		if len(cloudRanges) > 0 {
+			if cfg.Enrich.CloudRanges == nil {
+				cfg.Enrich.CloudRanges = map[string]string{}
+			}
+			if err := parseRangeFlags(cloudRanges, cfg.Enrich.CloudRanges); err != nil {
+				return err
+			}
+			changed = true
+		}
+
+		if cmd.Flags().Changed("asn-db") {
+			cfg.Enrich.ASNDB = asnDB
+			changed = true
+		}
+
 		if changed {
 			if err := config.Save(cfg); err != nil {
 				return fmt.Errorf("failed to save config: %w", err)
@@ -56,6 +93,13 @@ var configCmd = &cobra.Command{
 		fmt.Printf("max_connections: %d\n", cfg.MaxConnections)
 		fmt.Printf("default_program: %s\n", cfg.DefaultProgram)
 		fmt.Printf("default_platform: %s\n", cfg.DefaultPlatform)
+		fmt.Printf("user: %s\n", cfg.User)
+		fmt.Printf("max_body_size: %d\n", cfg.MaxBodySize)
+		fmt.Printf("label_rules: %s\n", cfg.LabelRules)
+		for _, provider := range slices.Sorted(maps.Keys(cfg.Enrich.CloudRanges)) {
+			fmt.Printf("cloud_ranges.%s: %s\n", provider, cfg.Enrich.CloudRanges[provider])
+		}
+		fmt.Printf("asn_db: %s\n", cfg.Enrich.ASNDB)
 
 		return nil
 	},
@@ -76,5 +120,10 @@ func init() {
 	configCmd.Flags().IntVar(&maxConnections, "max-connections", 0, "Maximum database connections")
 	configCmd.Flags().StringVar(&defaultProgram, "default-program", "", "Default program name")
 	configCmd.Flags().StringVar(&defaultPlatform, "default-platform", "", "Default platform name")
+	configCmd.Flags().StringVar(&userName, "user", "", "Your name on notes and assignments (default: login name)")
+	configCmd.Flags().IntVar(&maxBodySize, "max-body-size", 0, "Response body bytes stored per record, e.g. 1048576 (default 0: body storage off)")
+	configCmd.Flags().StringVar(&labelRules, "label-rules", "", "YAML file of labeling rules applied by store and retag (empty = built-in only)")
+	configCmd.Flags().StringArrayVar(&cloudRanges, "cloud-ranges", nil, "Provider IP range file for enrich as provider=path, repeatable (empty path removes)")
+	configCmd.Flags().StringVar(&asnDB, "asn-db", "", "ASN database for enrich, iptoasn TSV or .mmdb")
 	rootCmd.AddCommand(configCmd)
 }
@@ -0,0 +1,135 @@
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/itsmeashim/rdb/config"
+	"github.com/itsmeashim/rdb/db"
+	"github.com/spf13/cobra"
+)
+
+var dnsSince string
+
+var dnsCmd = &cobra.Command{
+	Use:   "dns",
+	Short: "DNS resolution history of hosts",
+	Long: `Every A, AAAA and CNAME value a host resolves to when stored is kept in the
+DNS history with the first and last time it was seen, together with the
+records imported from passive DNS.
+
+Examples:
+  rdb dns history api.example.com
+  rdb dns changed --since 7d --program myprogram`,
+}
+
+var dnsHistoryCmd = &cobra.Command{
+	Use:   "history <host>",
+	Short: "Show the values a host resolved to over time",
+	Args:  cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		cfg, err := config.Load()
+		if err != nil {
+			return fmt.Errorf("failed to load config: %w", err)
+		}
+
+		if err := db.Init(cfg); err != nil {
+			return err
+		}
+		defer db.Close()
+
+		history, err := db.DNSHistory(context.Background(), args[0], filterProgram)
+		if err != nil {
+			return fmt.Errorf("failed to query DNS history: %w", err)
+		}
+
+		if outputJSON {
+			encoder := json.NewEncoder(os.Stdout)
+			for _, o := range history {
+				encoder.Encode(o)
+			}
+			return nil
+		}
+
+		if len(history) == 0 {
+			fmt.Println("no DNS history found")
+			return nil
+		}
+
+		w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+		for _, o := range history {
+			fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\t%s\n",
+				o.Type, o.Value, o.FirstSeen.Format(time.DateTime), o.LastSeen.Format(time.DateTime), o.Source, o.Program)
+		}
+		w.Flush()
+		return nil
+	},
+}
+
+var dnsChangedCmd = &cobra.Command{
+	Use:   "changed",
+	Short: "List hosts whose resolution changed recently",
+	Long: `List hosts that started resolving to new A, AAAA or CNAME values within
+--since, with the values seen before. Hosts first seen in that window are
+not listed.`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		within, err := parseDuration(dnsSince)
+		if err != nil {
+			return err
+		}
+
+		cfg, err := config.Load()
+		if err != nil {
+			return fmt.Errorf("failed to load config: %w", err)
+		}
+
+		if err := db.Init(cfg); err != nil {
+			return err
+		}
+		defer db.Close()
+
+		changes, err := db.DNSChanges(context.Background(), within, filterProgram)
+		if err != nil {
+			return fmt.Errorf("failed to query DNS changes: %w", err)
+		}
+
+		if outputJSON {
+			encoder := json.NewEncoder(os.Stdout)
+			for _, c := range changes {
+				encoder.Encode(c)
+			}
+			return nil
+		}
+
+		if len(changes) == 0 {
+			fmt.Println("no DNS changes found")
+			return nil
+		}
+
+		w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+		for _, c := range changes {
+			fmt.Fprintf(w, "%s\t%s\t%s -> %s\t%s\t%s\n",
+				c.Host, c.Type, truncate(strings.Join(c.Previous, ","), 40), truncate(strings.Join(c.Current, ","), 40),
+				c.ChangedAt.Format(time.DateTime), c.Program)
+		}
+		w.Flush()
+		return nil
+	},
+}
+
+func init() {
+	dnsHistoryCmd.Flags().StringVar(&filterProgram, "program", "", "Filter by program name")
+	dnsHistoryCmd.Flags().BoolVarP(&outputJSON, "json", "j", false, "Output as JSON")
+
+	dnsChangedCmd.Flags().StringVar(&dnsSince, "since", "7d", "Time window (e.g., 24h, 7d, 2w)")
+	dnsChangedCmd.Flags().StringVar(&filterProgram, "program", "", "Filter by program name")
+	dnsChangedCmd.Flags().BoolVarP(&outputJSON, "json", "j", false, "Output as JSON")
+
+	dnsCmd.AddCommand(dnsHistoryCmd, dnsChangedCmd)
+	rootCmd.AddCommand(dnsCmd)
+}
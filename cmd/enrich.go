@@ -0,0 +1,131 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"maps"
+	"slices"
+	"strings"
+
+	"github.com/itsmeashim/rdb/config"
+	"github.com/itsmeashim/rdb/db"
+	"github.com/itsmeashim/rdb/enrich"
+	"github.com/itsmeashim/rdb/models"
+	"github.com/spf13/cobra"
+)
+
+var (
+	enrichRanges []string
+	enrichASNDB  string
+	enrichAll    bool
+)
+
+var enrichCmd = &cobra.Command{
+	Use:   "enrich",
+	Short: "Classify stored IPs by cloud provider, CDN and ASN",
+	Long: `Classifies the A record IPs of stored records against local datasets: the
+IP range files published by cloud and CDN providers and an offline ASN
+database (iptoasn.com TSV or a GeoLite2-ASN compatible .mmdb). The results
+are filtered on with 'rdb list --cloud aws', '--asn 13335' and '--not-cdn'.
+
+Datasets are set with 'rdb config --cloud-ranges' and '--asn-db', or per
+run with --ranges and --asn-db. Runs are incremental: only IPs not yet
+classified are looked up, unless a dataset file changed since, in which
+case every IP is classified again.
+
+Examples:
+  curl -so aws.json https://ip-ranges.amazonaws.com/ip-ranges.json
+  curl -so cloudflare.txt https://www.cloudflare.com/ips-v4
+  curl -s https://iptoasn.com/data/ip2asn-v4.tsv.gz | gunzip > ip2asn-v4.tsv
+  rdb config --cloud-ranges aws=aws.json --cloud-ranges cloudflare=cloudflare.txt --asn-db ip2asn-v4.tsv
+  rdb enrich
+  rdb list --program myprogram --not-cdn`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		cfg, err := config.Load()
+		if err != nil {
+			return fmt.Errorf("failed to load config: %w", err)
+		}
+
+		rangeFiles := maps.Clone(cfg.Enrich.CloudRanges)
+		if rangeFiles == nil {
+			rangeFiles = map[string]string{}
+		}
+		if err := parseRangeFlags(enrichRanges, rangeFiles); err != nil {
+			return err
+		}
+		asnDB := enrichASNDB
+		if asnDB == "" {
+			asnDB = cfg.Enrich.ASNDB
+		}
+
+		e, err := enrich.New(rangeFiles, asnDB)
+		if err != nil {
+			return fmt.Errorf("failed to load datasets: %w (see 'rdb enrich --help')", err)
+		}
+		defer e.Close()
+
+		if err := db.Init(cfg); err != nil {
+			return err
+		}
+		defer db.Close()
+
+		ctx := context.Background()
+		ips, err := db.PendingIPs(ctx, e.Fingerprint, enrichAll)
+		if err != nil {
+			return fmt.Errorf("failed to query IPs: %w", err)
+		}
+
+		infos := make([]models.IPInfo, 0, len(ips))
+		for _, ip := range ips {
+			info, ok := e.Classify(ip)
+			if !ok {
+				// Stored anyway so invalid values are not retried every run.
+				info = models.IPInfo{IP: ip}
+			}
+			infos = append(infos, info)
+		}
+		if err := db.SaveIPInfo(ctx, infos, e.Fingerprint); err != nil {
+			return fmt.Errorf("failed to save results: %w", err)
+		}
+
+		counts, err := db.CloudCounts(ctx)
+		if err != nil {
+			return fmt.Errorf("failed to count results: %w", err)
+		}
+		var parts []string
+		for _, cloud := range slices.Sorted(maps.Keys(counts)) {
+			name := cloud
+			if name == "" {
+				name = "self-hosted"
+			}
+			parts = append(parts, fmt.Sprintf("%s %d", name, counts[cloud]))
+		}
+		fmt.Printf("enriched %d IPs (total: %s)\n", len(infos), strings.Join(parts, ", "))
+		return nil
+	},
+}
+
+// parseRangeFlags adds provider=path pairs to files; an empty path removes
+// the provider.
+func parseRangeFlags(pairs []string, files map[string]string) error {
+	for _, pair := range pairs {
+		provider, path, ok := strings.Cut(pair, "=")
+		provider = strings.ToLower(strings.TrimSpace(provider))
+		if !ok || provider == "" {
+			return fmt.Errorf("invalid range file %q: use provider=path, e.g. aws=ip-ranges.json", pair)
+		}
+		if path == "" {
+			delete(files, provider)
+			continue
+		}
+		files[provider] = path
+	}
+	return nil
+}
+
+func init() {
+	enrichCmd.Flags().StringArrayVar(&enrichRanges, "ranges", nil, "Provider IP range file as provider=path, repeatable (added to cloud_ranges from config)")
+	enrichCmd.Flags().StringVar(&enrichASNDB, "asn-db", "", "ASN database, iptoasn TSV or .mmdb (default: asn_db from config)")
+	enrichCmd.Flags().BoolVar(&enrichAll, "all", false, "Classify every stored IP again")
+	rootCmd.AddCommand(enrichCmd)
+}
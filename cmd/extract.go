@@ -0,0 +1,227 @@
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"slices"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/itsmeashim/rdb/config"
+	"github.com/itsmeashim/rdb/db"
+	"github.com/itsmeashim/rdb/extract"
+	"github.com/spf13/cobra"
+)
+
+var (
+	extractRulesFile string
+	extractKind      string
+	extractRule      string
+	extractStatus    string
+	extractValue     string
+)
+
+// findingStatuses is the review vocabulary of extracted values, which are
+// findings to confirm or dismiss; hosts and URLs use assetStatuses instead.
+var findingStatuses = []string{"new", "confirmed", "false-positive", "reported", "ignored"}
+
+var extractCmd = &cobra.Command{
+	Use:   "extract <kind>... | all",
+	Short: "Extract secrets, endpoints, emails, S3 buckets and JS files from stored bodies",
+	Long: `Run the regex rule pack over stored response bodies and save the matches.
+Bodies are only stored once enabled with 'rdb config --max-body-size'.
+
+Built-in kinds: secrets, endpoints, emails, s3-buckets, js-files. Add or
+override rules with a YAML file:
+
+  rules:
+    - name: internal-host
+      kind: endpoints
+      pattern: '[a-z0-9.-]+\.corp\.example\.com'
+    - name: firebase-url
+      kind: secrets
+      pattern: '([a-z0-9-]+)\.firebaseio\.com'
+      group: 1
+
+Only bodies not yet processed for a kind are scanned, so re-running is cheap.
+Values are deduplicated per program.
+
+Examples:
+  rdb extract secrets --program myprogram
+  rdb extract all --rules my-rules.yaml
+  rdb extract list --kind secrets --status new
+  rdb extract triage 12 15 --status false-positive`,
+	Args: cobra.MinimumNArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		rules, err := extract.Rules(extractRulesFile)
+		if err != nil {
+			return err
+		}
+
+		kinds := extract.Kinds(rules)
+		if !slices.Contains(args, "all") {
+			for _, k := range args {
+				if !slices.Contains(kinds, k) {
+					return fmt.Errorf("unknown kind %q (available: %s)", k, strings.Join(kinds, ", "))
+				}
+			}
+			kinds = args
+		}
+
+		cfg, err := config.Load()
+		if err != nil {
+			return fmt.Errorf("failed to load config: %w", err)
+		}
+
+		if err := db.Init(cfg); err != nil {
+			return err
+		}
+		defer db.Close()
+
+		opts, err := listOptions()
+		if err != nil {
+			return err
+		}
+
+		ctx := context.Background()
+		for _, kind := range kinds {
+			refs, err := db.PendingBodies(ctx, opts, kind)
+			if err != nil {
+				return fmt.Errorf("failed to query bodies: %w", err)
+			}
+
+			var added int64
+			for _, ref := range refs {
+				body, err := db.LoadBody(ctx, ref.Hash)
+				if err != nil {
+					fmt.Fprintf(os.Stderr, "warning: failed to load body %s: %v\n", ref.Hash, err)
+					continue
+				}
+				n, err := db.SaveExtractions(ctx, ref, kind, extract.Find(rules, kind, body))
+				if err != nil {
+					return fmt.Errorf("failed to save extractions: %w", err)
+				}
+				added += n
+			}
+			fmt.Printf("%s: scanned %d bodies, %d new values\n", kind, len(refs), added)
+		}
+		return nil
+	},
+}
+
+var extractListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List extracted values",
+	RunE: func(cmd *cobra.Command, args []string) error {
+		cfg, err := config.Load()
+		if err != nil {
+			return fmt.Errorf("failed to load config: %w", err)
+		}
+
+		if err := db.Init(cfg); err != nil {
+			return err
+		}
+		defer db.Close()
+
+		results, err := db.ListExtractions(context.Background(), db.ExtractionOptions{
+			Program: filterProgram,
+			Kind:    extractKind,
+			Rule:    extractRule,
+			Status:  extractStatus,
+			Value:   extractValue,
+			Limit:   limit,
+		})
+		if err != nil {
+			return fmt.Errorf("failed to query extractions: %w", err)
+		}
+
+		if outputJSON {
+			encoder := json.NewEncoder(os.Stdout)
+			for _, r := range results {
+				encoder.Encode(r)
+			}
+			return nil
+		}
+
+		if len(results) == 0 {
+			fmt.Println("no extractions found")
+			return nil
+		}
+
+		if separator != "" {
+			for _, r := range results {
+				fmt.Println(strings.Join([]string{strconv.FormatInt(r.ID, 10), r.Kind, r.Rule, r.Value,
+					r.Status, r.URL, r.Program}, separator))
+			}
+			return nil
+		}
+
+		w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+		for _, r := range results {
+			fmt.Fprintf(w, "%d\t%s\t%s\t%s\t%s\t%s\t%s\n",
+				r.ID, r.Kind, r.Rule, truncate(r.Value, 80), r.Status, r.URL, r.Program)
+		}
+		w.Flush()
+		return nil
+	},
+}
+
+var extractTriageCmd = &cobra.Command{
+	Use:   "triage <id>...",
+	Short: "Set the finding status of extracted values",
+	Args:  cobra.MinimumNArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if !slices.Contains(findingStatuses, extractStatus) {
+			return fmt.Errorf("invalid --status %q (valid: %s)", extractStatus, strings.Join(findingStatuses, ", "))
+		}
+
+		ids := make([]int64, 0, len(args))
+		for _, a := range args {
+			id, err := strconv.ParseInt(a, 10, 64)
+			if err != nil {
+				return fmt.Errorf("invalid id %q", a)
+			}
+			ids = append(ids, id)
+		}
+
+		cfg, err := config.Load()
+		if err != nil {
+			return fmt.Errorf("failed to load config: %w", err)
+		}
+
+		if err := db.Init(cfg); err != nil {
+			return err
+		}
+		defer db.Close()
+
+		n, err := db.SetExtractionStatus(context.Background(), ids, extractStatus)
+		if err != nil {
+			return fmt.Errorf("failed to update extractions: %w", err)
+		}
+		fmt.Printf("updated %d extractions\n", n)
+		return nil
+	},
+}
+
+func init() {
+	addFilterFlags(extractCmd)
+	extractCmd.Flags().StringVar(&extractRulesFile, "rules", "", "YAML file with additional or overriding rules")
+
+	extractListCmd.Flags().StringVar(&filterProgram, "program", "", "Filter by program name")
+	extractListCmd.Flags().StringVar(&extractKind, "kind", "", "Filter by kind")
+	extractListCmd.Flags().StringVar(&extractRule, "rule", "", "Filter by rule name")
+	extractListCmd.Flags().StringVar(&extractStatus, "status", "", "Filter by finding status ("+strings.Join(findingStatuses, ", ")+")")
+	extractListCmd.Flags().StringVar(&extractValue, "value", "", "Filter by value (partial match)")
+	extractListCmd.Flags().IntVarP(&limit, "limit", "n", 0, "Limit number of results (0 = all)")
+	extractListCmd.Flags().BoolVarP(&outputJSON, "json", "j", false, "Output as JSON")
+	extractListCmd.Flags().StringVarP(&separator, "sep", "s", "", "Field separator for piping (e.g., ',' or '|')")
+
+	extractTriageCmd.Flags().StringVar(&extractStatus, "status", "", "New finding status ("+strings.Join(findingStatuses, ", ")+")")
+	extractTriageCmd.MarkFlagRequired("status")
+
+	extractCmd.AddCommand(extractListCmd, extractTriageCmd)
+	rootCmd.AddCommand(extractCmd)
+}
@@ -0,0 +1,151 @@
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/itsmeashim/rdb/config"
+	"github.com/itsmeashim/rdb/db"
+	"github.com/itsmeashim/rdb/models"
+	"github.com/spf13/cobra"
+)
+
+var (
+	grepIgnoreCase   bool
+	grepHeadersOnly  bool
+	grepBodyOnly     bool
+	grepMaxPerRecord int
+	grepContext      int
+)
+
+// grepMatch is one regex match in a stored response
+type grepMatch struct {
+	ID      int64  `json:"id"`
+	URL     string `json:"url"`
+	Program string `json:"program"`
+	Where   string `json:"where"`
+	Match   string `json:"match"`
+}
+
+var grepCmd = &cobra.Command{
+	Use:   "grep <regex>",
+	Short: "Search stored response bodies and headers",
+	Long: `Search the stored response bodies and headers of records matching the list
+filters with a regular expression (RE2 syntax). Bodies are only stored
+once enabled with 'rdb config --max-body-size'.
+
+Examples:
+  rdb grep 'api[_-]?key' --program myprogram
+  rdb grep -i 'index of /' --status 200
+  rdb grep --headers-only 'x-debug'`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		pattern := args[0]
+		if grepIgnoreCase {
+			pattern = "(?i)" + pattern
+		}
+		re, err := regexp.Compile(pattern)
+		if err != nil {
+			return fmt.Errorf("invalid regex: %w", err)
+		}
+
+		cfg, err := config.Load()
+		if err != nil {
+			return fmt.Errorf("failed to load config: %w", err)
+		}
+
+		if err := db.Init(cfg); err != nil {
+			return err
+		}
+		defer db.Close()
+
+		opts, err := listOptions()
+		if err != nil {
+			return err
+		}
+
+		encoder := json.NewEncoder(os.Stdout)
+		found := 0
+		err = db.EachResponse(context.Background(), opts, func(r models.Response) error {
+			for _, m := range grepResponse(re, r) {
+				if limit > 0 && found >= limit {
+					return errStopIteration
+				}
+				found++
+				if outputJSON {
+					encoder.Encode(m)
+					continue
+				}
+				fmt.Printf("%s\t%s\t%s\n", m.URL, m.Where, m.Match)
+			}
+			if limit > 0 && found >= limit {
+				return errStopIteration
+			}
+			return nil
+		})
+		if err != nil && !errors.Is(err, errStopIteration) {
+			return fmt.Errorf("failed to search responses: %w", err)
+		}
+
+		if found == 0 && !outputJSON {
+			fmt.Println("no matches found")
+		}
+		return nil
+	},
+}
+
+// errStopIteration ends a row callback loop early without reporting an error.
+var errStopIteration = errors.New("stop iteration")
+
+func grepResponse(re *regexp.Regexp, r models.Response) []grepMatch {
+	n := grepMaxPerRecord
+	if n <= 0 {
+		n = -1
+	}
+
+	var matches []grepMatch
+	add := func(where, text string) {
+		for _, loc := range re.FindAllStringIndex(text, n) {
+			start, end := max(loc[0]-grepContext, 0), min(loc[1]+grepContext, len(text))
+			snippet := strings.Join(strings.Fields(text[start:end]), " ")
+			matches = append(matches, grepMatch{ID: r.ID, URL: r.URL, Program: r.Program, Where: where, Match: snippet})
+		}
+	}
+
+	if !grepBodyOnly {
+		names := make([]string, 0, len(r.Headers))
+		for name := range r.Headers {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		for _, name := range names {
+			add("header", fmt.Sprintf("%s: %v", name, r.Headers[name]))
+		}
+	}
+	if !grepHeadersOnly && len(r.Body) > 0 {
+		add("body", string(r.Body))
+	}
+
+	if n > 0 && len(matches) > n {
+		matches = matches[:n]
+	}
+	return matches
+}
+
+func init() {
+	addFilterFlags(grepCmd)
+	grepCmd.Flags().BoolVarP(&grepIgnoreCase, "ignore-case", "i", false, "Case-insensitive match")
+	grepCmd.Flags().BoolVar(&grepHeadersOnly, "headers-only", false, "Only search response headers")
+	grepCmd.Flags().BoolVar(&grepBodyOnly, "body-only", false, "Only search response bodies")
+	grepCmd.Flags().IntVar(&grepMaxPerRecord, "max-per-record", 5, "Maximum matches shown per record (0 = all)")
+	grepCmd.Flags().IntVarP(&grepContext, "context", "C", 30, "Characters of context around each match")
+	grepCmd.Flags().IntVarP(&limit, "limit", "n", 0, "Stop after this many matches (0 = all)")
+	grepCmd.Flags().BoolVarP(&outputJSON, "json", "j", false, "Output as JSON")
+	rootCmd.AddCommand(grepCmd)
+}
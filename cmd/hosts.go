@@ -0,0 +1,102 @@
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/itsmeashim/rdb/config"
+	"github.com/itsmeashim/rdb/db"
+	"github.com/spf13/cobra"
+)
+
+var hostsCmd = &cobra.Command{
+	Use:   "hosts",
+	Short: "List hosts with their ports, schemes, status codes, tech and IPs",
+	Long: `List one line per host, aggregating the ports, schemes, status codes, tech
+and IPs of its stored records.
+
+Supports the same filters as list; only matching records are aggregated.`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		cfg, err := config.Load()
+		if err != nil {
+			return fmt.Errorf("failed to load config: %w", err)
+		}
+
+		if err := db.Init(cfg); err != nil {
+			return err
+		}
+		defer db.Close()
+
+		opts, err := listOptions()
+		if err != nil {
+			return err
+		}
+
+		results, err := db.ListHosts(context.Background(), opts)
+		if err != nil {
+			return fmt.Errorf("failed to query hosts: %w", err)
+		}
+
+		if outputJSON {
+			encoder := json.NewEncoder(os.Stdout)
+			for _, r := range results {
+				encoder.Encode(r)
+			}
+			return nil
+		}
+
+		if len(results) == 0 {
+			fmt.Println("no hosts found")
+			return nil
+		}
+
+		if separator != "" {
+			for _, r := range results {
+				fmt.Println(strings.Join([]string{
+					r.Host, strings.Join(r.Ports, ","), strings.Join(r.Schemes, ","),
+					joinInts(r.StatusCodes), strings.Join(r.Tech, ","), strings.Join(r.A, ","),
+					strings.Join(r.Programs, ","), strings.Join(r.Sources, ","),
+				}, separator))
+			}
+			return nil
+		}
+
+		w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+		for _, r := range results {
+			fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\t%s\t%s\t%s\n",
+				r.Host, strings.Join(r.Ports, ","), strings.Join(r.Schemes, ","),
+				joinInts(r.StatusCodes), truncate(strings.Join(r.Tech, ","), 30),
+				truncate(strings.Join(r.A, ","), 30), strings.Join(r.Programs, ","), strings.Join(r.Sources, ","))
+		}
+		w.Flush()
+		return nil
+	},
+}
+
+func joinInts(values []int32) string {
+	parts := make([]string, len(values))
+	for i, v := range values {
+		parts[i] = strconv.Itoa(int(v))
+	}
+	return strings.Join(parts, ",")
+}
+
+func truncate(s string, max int) string {
+	if len(s) > max {
+		return s[:max-3] + "..."
+	}
+	return s
+}
+
+func init() {
+	addFilterFlags(hostsCmd)
+	hostsCmd.Flags().IntVarP(&limit, "limit", "n", 0, "Limit number of results (0 = all)")
+	hostsCmd.Flags().BoolVarP(&outputJSON, "json", "j", false, "Output as JSON")
+	hostsCmd.Flags().StringVarP(&separator, "sep", "s", "", "Field separator for piping (e.g., ',' or '|')")
+	rootCmd.AddCommand(hostsCmd)
+}
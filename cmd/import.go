@@ -0,0 +1,223 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"slices"
+	"strings"
+
+	"github.com/itsmeashim/rdb/config"
+	"github.com/itsmeashim/rdb/db"
+	"github.com/itsmeashim/rdb/domain"
+	"github.com/itsmeashim/rdb/importer"
+	"github.com/itsmeashim/rdb/label"
+	"github.com/itsmeashim/rdb/models"
+	"github.com/spf13/cobra"
+)
+
+var (
+	importFormat string
+	importSource string
+)
+
+var importCmd = &cobra.Command{
+	Use:   "import [file...]",
+	Short: "Import output of other recon tools",
+	Long: `Imports the output of other recon tools as records, reading the files given
+or stdin. Supported formats:
+
+  nmap      nmap XML (-oX), one record per open port
+  masscan   masscan JSON (-oJ), one record per open port
+  ffuf      ffuf JSON (-of json)
+  gau       URL list, one per line
+  wayback   URL list, one per line (waybackurls)
+  burp      Burp Suite "Save items" XML export
+  har       HTTP Archive from a browser or proxy, stored as request/response
+            pairs (see 'rdb requests'); response bodies are kept up to
+            max_body_size, or 1 MiB when record body storage is off
+  crtsh     crt.sh JSON (?output=json), stored as subdomain history
+  pdns-json passive DNS (Common Output Format, e.g. DNSDB), stored as
+            subdomain and DNS history (see 'rdb subdomains')
+
+Records are tagged with their source (the format name, or --source) so they
+can be told apart from httpx probes, see 'rdb list --source'. URL lists are
+historic data: URLs already imported from the same source for the program
+are skipped.
+
+  nmap -sV -oX - example.com | rdb import --format nmap -p myprogram
+  rdb import --format wayback -p myprogram urls.txt`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		formats := importFormats()
+		if !slices.Contains(formats, importFormat) {
+			return fmt.Errorf("invalid --format %q (valid: %s)", importFormat, strings.Join(formats, ", "))
+		}
+
+		cfg, err := config.Load()
+		if err != nil {
+			return fmt.Errorf("failed to load config: %w", err)
+		}
+
+		if err := db.Init(cfg); err != nil {
+			return err
+		}
+		defer db.Close()
+
+		if program == "" {
+			program = cfg.DefaultProgram
+		}
+		if platform == "" {
+			platform = cfg.DefaultPlatform
+		}
+		source := importSource
+		if source == "" {
+			source = importFormat
+		}
+
+		if labelRulesFile == "" {
+			labelRulesFile = cfg.LabelRules
+		}
+		rules, err := label.Rules(labelRulesFile)
+		if err != nil {
+			return err
+		}
+
+		ctx := context.Background()
+		var seen map[string]bool
+		if importer.Static(importFormat) {
+			if seen, err = db.SourceURLs(ctx, program, source); err != nil {
+				return fmt.Errorf("failed to load imported URLs: %w", err)
+			}
+		}
+
+		count, skipped := 0, 0
+		storeRecord := func(data models.HTTPXData) error {
+			if seen != nil {
+				if seen[data.URL] {
+					skipped++
+					return nil
+				}
+				seen[data.URL] = true
+			}
+
+			data.Source = source
+			data.Program = program
+			data.Platform = platform
+			data.Hostname = domain.Hostname(data.URL, data.Input, data.Host)
+			data.Labels = label.Apply(rules, &data)
+
+			if err := db.Insert(ctx, &data); err != nil {
+				fmt.Fprintf(os.Stderr, "warning: failed to insert: %v\n", err)
+				return nil
+			}
+			count++
+			return nil
+		}
+		storeRequest := func(req models.HTTPRequest) error {
+			req.Source = source
+			req.Program = program
+			req.Platform = platform
+
+			added, err := db.InsertRequest(ctx, &req)
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "warning: failed to insert: %v\n", err)
+				return nil
+			}
+			if added {
+				count++
+			} else {
+				skipped++
+			}
+			return nil
+		}
+
+		// Observations are saved together so repeated names are merged.
+		var observations []models.DNSObservation
+		storeObservation := func(o models.DNSObservation) error {
+			o.Source = source
+			observations = append(observations, o)
+			return nil
+		}
+
+		read := func(r io.Reader) error {
+			return importer.Read(importFormat, r, storeRecord)
+		}
+		what := "records"
+		switch {
+		case slices.Contains(importer.RequestFormats, importFormat):
+			read = func(r io.Reader) error {
+				return importer.ReadRequests(importFormat, r, storeRequest)
+			}
+			what = "requests"
+		case slices.Contains(importer.ObservationFormats, importFormat):
+			read = func(r io.Reader) error {
+				return importer.ReadObservations(importFormat, r, storeObservation)
+			}
+		}
+
+		if len(args) == 0 {
+			stat, _ := os.Stdin.Stat()
+			if (stat.Mode() & os.ModeCharDevice) != 0 {
+				return fmt.Errorf("no input provided. Pass files or pipe %s output to this command", importFormat)
+			}
+			if err := read(os.Stdin); err != nil {
+				return fmt.Errorf("failed to read input: %w", err)
+			}
+		}
+		for _, path := range args {
+			if err := importFile(path, read); err != nil {
+				return fmt.Errorf("failed to read %s: %w", path, err)
+			}
+		}
+
+		if observations != nil {
+			added, err := db.SaveObservations(ctx, program, observations)
+			if err != nil {
+				return fmt.Errorf("failed to save observations: %w", err)
+			}
+			fmt.Printf("imported %d observations (%d new subdomains)\n", len(observations), added)
+			return nil
+		}
+
+		// Link captured traffic to the host records, including traffic
+		// imported before its host was stored.
+		if err := db.LinkRequests(ctx, program); err != nil {
+			fmt.Fprintf(os.Stderr, "warning: failed to link requests: %v\n", err)
+		}
+
+		if skipped > 0 {
+			fmt.Printf("imported %d %s (%d already imported)\n", count, what, skipped)
+		} else {
+			fmt.Printf("imported %d %s\n", count, what)
+		}
+		return nil
+	},
+}
+
+func importFormats() []string {
+	formats := append(slices.Clone(importer.Formats), importer.RequestFormats...)
+	return append(formats, importer.ObservationFormats...)
+}
+
+func importFile(path string, read func(io.Reader) error) error {
+	if path == "-" {
+		return read(os.Stdin)
+	}
+	f, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	return read(f)
+}
+
+func init() {
+	importCmd.Flags().StringVar(&importFormat, "format", "", "Input format ("+strings.Join(importFormats(), ", ")+")")
+	importCmd.Flags().StringVarP(&program, "program", "p", "", "Program name (e.g., bugcrowd-program)")
+	importCmd.Flags().StringVar(&platform, "platform", "", "Platform name (e.g., hackerone, bugcrowd)")
+	importCmd.Flags().StringVar(&importSource, "source", "", "Source recorded on the records (default: the format name)")
+	importCmd.Flags().StringVar(&labelRulesFile, "label-rules", "", "YAML file of labeling rules (default: label_rules from config)")
+	importCmd.MarkFlagRequired("format")
+	rootCmd.AddCommand(importCmd)
+}
@@ -0,0 +1,82 @@
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/itsmeashim/rdb/config"
+	"github.com/itsmeashim/rdb/db"
+	"github.com/spf13/cobra"
+)
+
+var ipsCmd = &cobra.Command{
+	Use:   "ips",
+	Short: "List IPs with the hosts resolving to them",
+	Long: `List one line per IP address with the hosts that resolve to it, the host count
+and the programs involved. IPs shared by many hosts are listed first, which
+helps spot shared hosting and origin IPs behind CDNs.
+
+Supports the same filters as list.`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		cfg, err := config.Load()
+		if err != nil {
+			return fmt.Errorf("failed to load config: %w", err)
+		}
+
+		if err := db.Init(cfg); err != nil {
+			return err
+		}
+		defer db.Close()
+
+		opts, err := listOptions()
+		if err != nil {
+			return err
+		}
+
+		results, err := db.ListIPs(context.Background(), opts)
+		if err != nil {
+			return fmt.Errorf("failed to query IPs: %w", err)
+		}
+
+		if outputJSON {
+			encoder := json.NewEncoder(os.Stdout)
+			for _, r := range results {
+				encoder.Encode(r)
+			}
+			return nil
+		}
+
+		if len(results) == 0 {
+			fmt.Println("no IPs found")
+			return nil
+		}
+
+		if separator != "" {
+			for _, r := range results {
+				fmt.Printf("%s%s%d%s%s%s%s\n", r.IP, separator, r.HostCount, separator,
+					strings.Join(r.Hosts, ","), separator, strings.Join(r.Programs, ","))
+			}
+			return nil
+		}
+
+		w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+		for _, r := range results {
+			fmt.Fprintf(w, "%s\t%d\t%s\t%s\n",
+				r.IP, r.HostCount, truncate(strings.Join(r.Hosts, ","), 60), strings.Join(r.Programs, ","))
+		}
+		w.Flush()
+		return nil
+	},
+}
+
+func init() {
+	addFilterFlags(ipsCmd)
+	ipsCmd.Flags().IntVarP(&limit, "limit", "n", 0, "Limit number of results (0 = all)")
+	ipsCmd.Flags().BoolVarP(&outputJSON, "json", "j", false, "Output as JSON")
+	ipsCmd.Flags().StringVarP(&separator, "sep", "s", "", "Field separator for piping (e.g., ',' or '|')")
+	rootCmd.AddCommand(ipsCmd)
+}
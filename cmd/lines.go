@@ -0,0 +1,60 @@
+package cmd
+
+import (
+	"bufio"
+	"bytes"
+	"io"
+)
+
+// minLineSize is the smallest input line limit; httpx JSON lines with a full
+// response (-irr) easily exceed bufio.Scanner's default.
+const minLineSize = 64 << 20
+
+// lineLimit returns the longest input line accepted when bodies of up to
+// maxBodySize bytes are stored, leaving room for JSON escaping.
+func lineLimit(maxBodySize int) int {
+	return max(minLineSize, 8*maxBodySize)
+}
+
+// readLine reads the next line of r without its line ending. A line longer
+// than limit is consumed and reported as too long instead of returned.
+// Every byte read, including those of a line too long, is copied to tee
+// when it is not nil, so a pass-through never drops input. It returns
+// io.EOF once r is exhausted.
+func readLine(r *bufio.Reader, limit int, tee io.Writer) (line []byte, tooLong bool, err error) {
+	read := false
+	for {
+		chunk, err := r.ReadSlice('\n')
+		if len(chunk) > 0 {
+			read = true
+			if tee != nil {
+				tee.Write(chunk)
+			}
+			if !tooLong {
+				if len(line)+len(chunk) > limit+2 {
+					tooLong, line = true, nil
+				} else {
+					line = append(line, chunk...)
+				}
+			}
+		}
+		if err == bufio.ErrBufferFull {
+			continue
+		}
+		if err == io.EOF && read {
+			if tee != nil && !bytes.HasSuffix(chunk, []byte("\n")) {
+				tee.Write([]byte("\n"))
+			}
+			err = nil
+		}
+		if err != nil {
+			return nil, false, err
+		}
+		line = bytes.TrimSuffix(line, []byte("\n"))
+		line = bytes.TrimSuffix(line, []byte("\r"))
+		if len(line) > limit {
+			tooLong, line = true, nil
+		}
+		return line, tooLong, nil
+	}
+}
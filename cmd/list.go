@@ -5,11 +5,14 @@ import (
 	"encoding/json"
 	"fmt"
 	"os"
+	"slices"
 	"strings"
 	"text/tabwriter"
 
+	"github.com/itsmeashim/rdb/cluster"
 	"github.com/itsmeashim/rdb/config"
 	"github.com/itsmeashim/rdb/db"
+	"github.com/itsmeashim/rdb/technology"
 	"github.com/spf13/cobra"
 )
 
@@ -19,24 +22,41 @@ var (
 	filterInput       string
 	filterTitle       string
 	filterA           string
+	filterCNAME       string
 	filterWebserver   string
 	filterTech        string
+	filterTechVersion string
+	filterHeader      string
 	filterHost        string
 	filterScheme      string
 	filterPort        string
 	filterMethod      string
 	filterPath        string
 	filterLocation    string
+	filterRedirectsTo string
+	externalRedirect  bool
 	filterContentType string
 	filterStatusCode  int
 	filterProgram     string
 	filterPlatform    string
+	filterSource      string
+	filterRootDomain  string
+	excludeWildcards  bool
+	filterLabel       string
+	filterTriage      string
+	filterAssignee    string
+	filterCloud       string
+	filterASN         int
+	notCDN            bool
+	includeGone       bool
 	sortBy            string
 	sortOrder         string
 	limit             int
 	outputJSON        bool
 	separator         string
 	listURLs          bool
+	collapse          bool
+	collapseMin       int
 )
 
 var listCmd = &cobra.Command{
@@ -54,27 +74,15 @@ var listCmd = &cobra.Command{
 		}
 		defer db.Close()
 
-		opts := db.ListOptions{
-			Query:       filterQuery,
-			URL:         filterURL,
-			Input:       filterInput,
-			Title:       filterTitle,
-			A:           filterA,
-			Webserver:   filterWebserver,
-			Tech:        filterTech,
-			Host:        filterHost,
-			Scheme:      filterScheme,
-			Port:        filterPort,
-			Method:      filterMethod,
-			Path:        filterPath,
-			Location:    filterLocation,
-			ContentType: filterContentType,
-			StatusCode:  filterStatusCode,
-			Program:     filterProgram,
-			Platform:    filterPlatform,
-			SortBy:      sortBy,
-			SortOrder:   sortOrder,
-			Limit:       limit,
+		opts, err := listOptions()
+		if err != nil {
+			return err
+		}
+		opts.SortBy = sortBy
+		opts.SortOrder = sortOrder
+		if collapse {
+			// Collapse before limiting so the limit counts what is shown.
+			opts.Limit = 0
 		}
 
 		results, err := db.List(context.Background(), opts)
@@ -82,6 +90,13 @@ var listCmd = &cobra.Command{
 			return fmt.Errorf("failed to query data: %w", err)
 		}
 
+		if collapse {
+			results = cluster.Collapse(results, clusterTolerance, collapseMin)
+			if limit > 0 && len(results) > limit {
+				results = results[:limit]
+			}
+		}
+
 		if listURLs {
 			for _, r := range results {
 				fmt.Fprintln(os.Stdout, r.URL)
@@ -105,9 +120,9 @@ var listCmd = &cobra.Command{
 		if separator != "" {
 			for _, r := range results {
 				tech := strings.Join(r.Tech, ",")
-				fmt.Printf("%s%s%d%s%s%s%s%s%s%s%s%s%s\n",
+				fmt.Printf("%s%s%d%s%s%s%s%s%s%s%s%s%s%s%s\n",
 					r.URL, separator, r.StatusCode, separator, r.Webserver, separator,
-					tech, separator, r.Title, separator, r.Program, separator, r.Platform)
+					tech, separator, r.Title, separator, r.Program, separator, r.Platform, separator, r.Source)
 			}
 			return nil
 		}
@@ -122,37 +137,122 @@ var listCmd = &cobra.Command{
 			if len(tech) > 30 {
 				tech = tech[:27] + "..."
 			}
-			fmt.Fprintf(w, "%s\t%d\t%s\t%s\t%s\t%s\t%s\n",
-				r.URL, r.StatusCode, r.Webserver, tech, title, r.Program, r.Platform)
+			fmt.Fprintf(w, "%s\t%d\t%s\t%s\t%s\t%s\t%s\t%s\n",
+				r.URL, r.StatusCode, r.Webserver, tech, title, r.Program, r.Platform, r.Source)
 		}
 		w.Flush()
 		return nil
 	},
 }
 
+// listOptions builds db.ListOptions from the shared filter flags.
+func listOptions() (db.ListOptions, error) {
+	opts := db.ListOptions{
+		Query:       filterQuery,
+		URL:         filterURL,
+		Input:       filterInput,
+		Title:       filterTitle,
+		A:           filterA,
+		CNAME:       filterCNAME,
+		Webserver:   filterWebserver,
+		Tech:        filterTech,
+		Header:      filterHeader,
+		Host:        filterHost,
+		Scheme:      filterScheme,
+		Port:        filterPort,
+		Method:      filterMethod,
+		Path:        filterPath,
+		Location:    filterLocation,
+		RedirectsTo: filterRedirectsTo,
+		ContentType: filterContentType,
+		StatusCode:  filterStatusCode,
+		Program:     filterProgram,
+		Platform:    filterPlatform,
+		Source:      filterSource,
+		RootDomain:  filterRootDomain,
+		Limit:       limit,
+
+		ExcludeWildcards: excludeWildcards,
+		ExternalRedirect: externalRedirect,
+		Label:            filterLabel,
+		TriageStatus:     filterTriage,
+		Assignee:         resolveUser(filterAssignee),
+		Cloud:            filterCloud,
+		ASN:              filterASN,
+		NotCDN:           notCDN,
+		IncludeGone:      includeGone,
+	}
+
+	if filterTriage != "" && !slices.Contains(assetStatuses, filterTriage) {
+		return opts, fmt.Errorf("invalid --status-triage %q (valid: %s)", filterTriage, strings.Join(assetStatuses, ", "))
+	}
+
+	// --tech also accepts a version constraint, e.g. "PHP>=7,<8".
+	if technology.HasConstraint(filterTech) {
+		c, err := technology.ParseConstraint(filterTech)
+		if err != nil {
+			return opts, err
+		}
+		opts.Tech = ""
+		opts.TechVersion = append(opts.TechVersion, c)
+	}
+	if filterTechVersion != "" {
+		c, err := technology.ParseConstraint(filterTechVersion)
+		if err != nil {
+			return opts, err
+		}
+		opts.TechVersion = append(opts.TechVersion, c)
+	}
+
+	return opts, nil
+}
+
+// addFilterFlags registers the record filters shared by list-style commands.
+func addFilterFlags(c *cobra.Command) {
+	c.Flags().StringVarP(&filterQuery, "query", "q", "", "Search across common fields (url, input, title, host, webserver, content-type, tech, a, program, platform)")
+	c.Flags().StringVar(&filterURL, "url", "", "Filter by URL (partial match)")
+	c.Flags().StringVar(&filterInput, "input", "", "Filter by input (partial match)")
+	c.Flags().StringVar(&filterTitle, "title", "", "Filter by title (partial match)")
+	c.Flags().StringVar(&filterA, "a", "", "Filter by DNS A record (partial match)")
+	c.Flags().StringVar(&filterCNAME, "cname", "", "Filter by DNS CNAME record (partial match, httpx -cname)")
+	c.Flags().StringVar(&filterWebserver, "webserver", "", "Filter by webserver (partial match)")
+	c.Flags().StringVar(&filterTech, "tech", "", "Filter by technology (partial match, or a version constraint like 'PHP>=7,<8')")
+	c.Flags().StringVar(&filterTechVersion, "tech-version", "", "Filter by technology version constraint (e.g., 'nginx<1.20')")
+	c.Flags().StringVar(&filterHeader, "header", "", "Filter by response header, 'Name: value' (partial match) or 'Name' (present)")
+	c.Flags().StringVar(&filterHost, "host", "", "Filter by host (partial match)")
+	c.Flags().StringVar(&filterScheme, "scheme", "", "Filter by scheme (http/https)")
+	c.Flags().StringVar(&filterPort, "port", "", "Filter by port (exact)")
+	c.Flags().StringVar(&filterMethod, "method", "", "Filter by method (exact)")
+	c.Flags().StringVar(&filterPath, "path", "", "Filter by path (partial match)")
+	c.Flags().StringVar(&filterLocation, "location", "", "Filter by redirect location (partial match)")
+	c.Flags().StringVar(&filterRedirectsTo, "redirects-to", "", "Filter by redirect destination or any hop of the redirect chain (partial match)")
+	c.Flags().BoolVar(&externalRedirect, "external-redirect", false, "Only records redirecting to another registrable domain")
+	c.Flags().StringVar(&filterContentType, "content-type", "", "Filter by content-type (partial match)")
+	c.Flags().IntVar(&filterStatusCode, "status", 0, "Filter by HTTP status code (exact)")
+	c.Flags().StringVar(&filterProgram, "program", "", "Filter by program name")
+	c.Flags().StringVar(&filterPlatform, "platform", "", "Filter by platform name")
+	c.Flags().StringVar(&filterSource, "source", "", "Filter by data source (httpx, nmap, masscan, ffuf, gau, wayback, burp, ...)")
+	c.Flags().StringVar(&filterRootDomain, "root-domain", "", "Filter by registrable domain, e.g. example.co.uk (exact)")
+	c.Flags().StringVar(&filterLabel, "label", "", "Filter by label (exact), e.g. login-panel")
+	c.Flags().StringVar(&filterTriage, "status-triage", "", "Filter by asset status set with 'rdb triage' ("+strings.Join(assetStatuses, ", ")+")")
+	c.Flags().StringVar(&filterAssignee, "assignee", "", "Filter by assignee (\"me\" for yourself)")
+	c.Flags().StringVar(&filterCloud, "cloud", "", "Filter by cloud/CDN provider of an A record IP, e.g. aws, cloudflare, self-hosted (see 'rdb enrich')")
+	c.Flags().IntVar(&filterASN, "asn", 0, "Filter by ASN of an A record IP (see 'rdb enrich')")
+	c.Flags().BoolVar(&notCDN, "not-cdn", false, "Hide records resolving to CDN IPs (see 'rdb enrich')")
+	c.Flags().BoolVar(&includeGone, "include-gone", false, "Include assets marked gone by full scans (see 'rdb store --full-scan')")
+	c.Flags().BoolVar(&excludeWildcards, "exclude-wildcards", false, "Hide records of wildcard/catch-all subdomains (see 'rdb wildcards')")
+}
+
 func init() {
-	listCmd.Flags().StringVarP(&filterQuery, "query", "q", "", "Search across common fields (url, input, title, host, webserver, content-type, tech, a, program, platform)")
-	listCmd.Flags().StringVar(&filterURL, "url", "", "Filter by URL (partial match)")
-	listCmd.Flags().StringVar(&filterInput, "input", "", "Filter by input (partial match)")
-	listCmd.Flags().StringVar(&filterTitle, "title", "", "Filter by title (partial match)")
-	listCmd.Flags().StringVar(&filterA, "a", "", "Filter by DNS A record (partial match)")
-	listCmd.Flags().StringVar(&filterWebserver, "webserver", "", "Filter by webserver (partial match)")
-	listCmd.Flags().StringVar(&filterTech, "tech", "", "Filter by technology (partial match)")
-	listCmd.Flags().StringVar(&filterHost, "host", "", "Filter by host (partial match)")
-	listCmd.Flags().StringVar(&filterScheme, "scheme", "", "Filter by scheme (http/https)")
-	listCmd.Flags().StringVar(&filterPort, "port", "", "Filter by port (exact)")
-	listCmd.Flags().StringVar(&filterMethod, "method", "", "Filter by method (exact)")
-	listCmd.Flags().StringVar(&filterPath, "path", "", "Filter by path (partial match)")
-	listCmd.Flags().StringVar(&filterLocation, "location", "", "Filter by redirect location (partial match)")
-	listCmd.Flags().StringVar(&filterContentType, "content-type", "", "Filter by content-type (partial match)")
-	listCmd.Flags().IntVar(&filterStatusCode, "status", 0, "Filter by HTTP status code (exact)")
-	listCmd.Flags().StringVar(&filterProgram, "program", "", "Filter by program name")
-	listCmd.Flags().StringVar(&filterPlatform, "platform", "", "Filter by platform name")
-	listCmd.Flags().StringVar(&sortBy, "sort", "created_at", "Sort by field (url, input, title, host, scheme, port, method, path, location, content_type, status_code, content_length, words, lines, webserver, tech, program, platform, created_at)")
+	addFilterFlags(listCmd)
+	listCmd.Flags().StringVar(&sortBy, "sort", "created_at", "Sort by field (url, input, title, host, scheme, port, method, path, location, content_type, status_code, content_length, words, lines, webserver, tech, hostname, root_domain, program, platform, created_at)")
 	listCmd.Flags().StringVar(&sortOrder, "order", "desc", "Sort order (asc, desc)")
 	listCmd.Flags().IntVarP(&limit, "limit", "n", 0, "Limit number of results (0 = all)")
 	listCmd.Flags().BoolVarP(&outputJSON, "json", "j", false, "Output as JSON")
 	listCmd.Flags().StringVarP(&separator, "sep", "s", "", "Field separator for piping (e.g., ',' or '|')")
 	listCmd.Flags().BoolVar(&listURLs, "urls", false, "Only output URLs")
+	listCmd.Flags().BoolVar(&collapse, "collapse", false, "Show one record per cluster of similar responses (see rdb cluster)")
+	listCmd.Flags().IntVar(&collapseMin, "collapse-min", 5, "Minimum cluster size collapsed by --collapse")
+	listCmd.Flags().Float64Var(&clusterTolerance, "tolerance", 0.1, "Relative tolerance used by --collapse")
 	rootCmd.AddCommand(listCmd)
 }
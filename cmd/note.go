@@ -0,0 +1,163 @@
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/itsmeashim/rdb/config"
+	"github.com/itsmeashim/rdb/db"
+	"github.com/spf13/cobra"
+)
+
+var noteAuthor string
+
+var noteCmd = &cobra.Command{
+	Use:   "note",
+	Short: "Leave notes on hosts and URLs",
+	Long: `Notes record what was checked on an asset so others do not re-test it.
+A record id or URL attaches the note to that URL, anything else to the host.
+Notes are kept across re-stores.
+
+Examples:
+  rdb note add 1234 "checked for IDOR, nothing there"
+  rdb note add admin.example.com "basic auth, default creds fail"
+  rdb note list https://admin.example.com/login
+  rdb note list --program myprogram --author bob`,
+}
+
+var noteAddCmd = &cobra.Command{
+	Use:   "add <host|id|url> <text>...",
+	Short: "Add a note",
+	Args:  cobra.MinimumNArgs(2),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		cfg, err := config.Load()
+		if err != nil {
+			return fmt.Errorf("failed to load config: %w", err)
+		}
+
+		if err := db.Init(cfg); err != nil {
+			return err
+		}
+		defer db.Close()
+
+		ctx := context.Background()
+		targets, err := db.ResolveTargets(ctx, args[0], filterProgram)
+		if err != nil {
+			return err
+		}
+
+		id, err := db.AddNote(ctx, targets, currentUser(), strings.Join(args[1:], " "))
+		if err != nil {
+			return fmt.Errorf("failed to add note: %w", err)
+		}
+		fmt.Printf("added note %d\n", id)
+		return nil
+	},
+}
+
+var noteListCmd = &cobra.Command{
+	Use:   "list [host|id|url]",
+	Short: "List notes",
+	Long: `List notes, newest first. With a URL or record id, the notes on its host
+are included.`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		cfg, err := config.Load()
+		if err != nil {
+			return fmt.Errorf("failed to load config: %w", err)
+		}
+
+		if err := db.Init(cfg); err != nil {
+			return err
+		}
+		defer db.Close()
+
+		ctx := context.Background()
+		opts := db.NoteOptions{
+			Program: filterProgram,
+			Author:  resolveUser(noteAuthor),
+			Limit:   limit,
+		}
+		if len(args) == 1 {
+			if opts.Targets, err = db.ResolveTargets(ctx, args[0], filterProgram); err != nil {
+				return err
+			}
+		}
+
+		notes, err := db.ListNotes(ctx, opts)
+		if err != nil {
+			return fmt.Errorf("failed to query notes: %w", err)
+		}
+
+		if outputJSON {
+			encoder := json.NewEncoder(os.Stdout)
+			for _, n := range notes {
+				encoder.Encode(n)
+			}
+			return nil
+		}
+
+		if len(notes) == 0 {
+			fmt.Println("no notes found")
+			return nil
+		}
+
+		w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+		for _, n := range notes {
+			fmt.Fprintf(w, "%d\t%s\t%s\t%s\t%s\t%s\n",
+				n.ID, n.CreatedAt.Format(time.DateTime), n.Author, n.Value, n.Program, n.Text)
+		}
+		w.Flush()
+		return nil
+	},
+}
+
+var noteRmCmd = &cobra.Command{
+	Use:   "rm <note-id>",
+	Short: "Delete a note",
+	Args:  cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		id, err := strconv.ParseInt(args[0], 10, 64)
+		if err != nil {
+			return fmt.Errorf("invalid note id %q", args[0])
+		}
+
+		cfg, err := config.Load()
+		if err != nil {
+			return fmt.Errorf("failed to load config: %w", err)
+		}
+
+		if err := db.Init(cfg); err != nil {
+			return err
+		}
+		defer db.Close()
+
+		found, err := db.DeleteNote(context.Background(), id)
+		if err != nil {
+			return fmt.Errorf("failed to delete note: %w", err)
+		}
+		if !found {
+			return fmt.Errorf("note %d not found", id)
+		}
+		fmt.Printf("deleted note %d\n", id)
+		return nil
+	},
+}
+
+func init() {
+	noteAddCmd.Flags().StringVar(&filterProgram, "program", "", "Only note the host within this program (default: every program it was seen in)")
+
+	noteListCmd.Flags().StringVar(&filterProgram, "program", "", "Filter by program name")
+	noteListCmd.Flags().StringVar(&noteAuthor, "author", "", "Filter by author (\"me\" for yourself)")
+	noteListCmd.Flags().IntVarP(&limit, "limit", "n", 0, "Limit number of results (0 = all)")
+	noteListCmd.Flags().BoolVarP(&outputJSON, "json", "j", false, "Output as JSON")
+
+	noteCmd.AddCommand(noteAddCmd, noteListCmd, noteRmCmd)
+	rootCmd.AddCommand(noteCmd)
+}
@@ -0,0 +1,92 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/itsmeashim/rdb/config"
+	"github.com/itsmeashim/rdb/db"
+	"github.com/itsmeashim/rdb/models"
+	"github.com/itsmeashim/rdb/notify"
+	"github.com/spf13/cobra"
+)
+
+var notifyWebhook string
+
+var notifyCmd = &cobra.Command{
+	Use:   "notify",
+	Short: "Manage change notifications",
+	Long: `Change notifications are sent after rdb store for new hosts, new URLs,
+status transitions, new tech and title changes. Webhooks and rules are set in
+the "notifications" section of the config file.`,
+}
+
+var notifyTestCmd = &cobra.Command{
+	Use:   "test",
+	Short: "Send a sample digest to the configured webhooks",
+	RunE: func(cmd *cobra.Command, args []string) error {
+		cfg, err := config.Load()
+		if err != nil {
+			return fmt.Errorf("failed to load config: %w", err)
+		}
+
+		sample := []models.Change{
+			{Type: models.ChangeNewHost, Program: "test", Host: "new.example.com", URL: "https://new.example.com"},
+			{Type: models.ChangeStatus, Program: "test", Host: "admin.example.com", URL: "https://admin.example.com", From: "403", To: "200"},
+		}
+
+		sent := 0
+		for _, hook := range cfg.Notifications.Webhooks {
+			if notifyWebhook != "" && hook.Name != notifyWebhook {
+				continue
+			}
+			if err := notify.Send(context.Background(), hook, sample, cfg.Notifications.MaxEvents); err != nil {
+				return fmt.Errorf("failed to notify %s: %w", hook.Name, err)
+			}
+			fmt.Printf("sent test digest to %s\n", hook.Name)
+			sent++
+		}
+		if sent == 0 {
+			return fmt.Errorf("no matching webhooks configured")
+		}
+		return nil
+	},
+}
+
+// dispatchNotifications sends the notification digests for changes, with
+// the webhook state kept in the database.
+func dispatchNotifications(ctx context.Context, cfg config.NotifyConfig, changes []models.Change) {
+	if err := notify.Dispatch(ctx, cfg, changes, notifyState{}); err != nil {
+		for _, msg := range strings.Split(err.Error(), "\n") {
+			fmt.Fprintf(os.Stderr, "warning: %s\n", msg)
+		}
+	}
+}
+
+// notifyState implements notify.State on top of the database.
+type notifyState struct{}
+
+func (notifyState) LastNotified(ctx context.Context, webhook string) (time.Time, error) {
+	return db.LastNotified(ctx, webhook)
+}
+
+func (notifyState) MarkNotified(ctx context.Context, webhook string) error {
+	return db.MarkNotified(ctx, webhook)
+}
+
+func (notifyState) Queue(ctx context.Context, webhook string, changes []models.Change) error {
+	return db.QueueNotifications(ctx, webhook, changes)
+}
+
+func (notifyState) TakeQueued(ctx context.Context, webhook string) ([]models.Change, error) {
+	return db.TakeQueuedNotifications(ctx, webhook)
+}
+
+func init() {
+	notifyTestCmd.Flags().StringVar(&notifyWebhook, "webhook", "", "Only send to the webhook with this name")
+	notifyCmd.AddCommand(notifyTestCmd)
+	rootCmd.AddCommand(notifyCmd)
+}
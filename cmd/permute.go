@@ -0,0 +1,129 @@
+package cmd
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/itsmeashim/rdb/config"
+	"github.com/itsmeashim/rdb/db"
+	"github.com/itsmeashim/rdb/domain"
+	"github.com/itsmeashim/rdb/permute"
+	"github.com/spf13/cobra"
+)
+
+var (
+	permuteMax      int
+	permuteLearned  int
+	permuteWordFile string
+	permuteNoEnv    bool
+)
+
+var permuteCmd = &cobra.Command{
+	Use:   "permute",
+	Short: "Generate subdomain permutations of known hosts",
+	Long: `Generate altdns/dnsgen-style permutations of the hosts (URL hostnames and
+httpx inputs) of the records matching the filters, to brute-force likely
+siblings:
+
+  api.example.com -> api2.example.com, api-01.example.com       numbers
+                     api-dev.example.com, stagingapi.example.com joined words
+                     dev.example.com                            replaced label
+                     internal.api.example.com                   new label
+
+Words are environment words (dev, staging, qa, ...), the labels most often
+seen in the program's subdomains, and --words. Candidates already stored
+under the same registrable domains are dropped. Output stops at --max.
+
+Examples:
+  rdb permute --program myprogram | puredns resolve -r resolvers.txt
+  rdb permute --program myprogram --root-domain example.com --max 50000
+  rdb permute --program myprogram --words words.txt --no-env`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		cfg, err := config.Load()
+		if err != nil {
+			return fmt.Errorf("failed to load config: %w", err)
+		}
+
+		if err := db.Init(cfg); err != nil {
+			return err
+		}
+		defer db.Close()
+
+		opts, err := listOptions()
+		if err != nil {
+			return err
+		}
+		opts.Limit = 0
+
+		ctx := context.Background()
+		hosts, err := db.KnownHosts(ctx, opts)
+		if err != nil {
+			return fmt.Errorf("failed to query hosts: %w", err)
+		}
+
+		var words []string
+		if !permuteNoEnv {
+			words = append(words, permute.EnvWords...)
+		}
+		words = append(words, permute.LearnedWords(hosts, permuteLearned)...)
+		if permuteWordFile != "" {
+			data, err := os.ReadFile(permuteWordFile)
+			if err != nil {
+				return err
+			}
+			for _, line := range strings.Split(string(data), "\n") {
+				if w := strings.ToLower(strings.TrimSpace(line)); w != "" && !strings.HasPrefix(w, "#") {
+					words = append(words, w)
+				}
+			}
+		}
+		words = uniqueStrings(words)
+
+		var roots []string
+		for _, h := range hosts {
+			if root, _ := domain.Split(h); root != "" {
+				roots = append(roots, root)
+			}
+		}
+		exists, err := db.HostnamesUnder(ctx, uniqueStrings(roots))
+		if err != nil {
+			return fmt.Errorf("failed to query hosts: %w", err)
+		}
+		for _, h := range hosts {
+			exists[h] = true
+		}
+
+		out := bufio.NewWriter(os.Stdout)
+		defer out.Flush()
+		n := permute.Generate(hosts, words, exists, permuteMax, func(candidate string) {
+			fmt.Fprintln(out, candidate)
+		})
+		fmt.Fprintf(os.Stderr, "generated %d candidates from %d hosts and %d words\n", n, len(hosts), len(words))
+		return nil
+	},
+}
+
+// uniqueStrings returns s without duplicates, keeping the first occurrence.
+func uniqueStrings(s []string) []string {
+	seen := map[string]bool{}
+	out := s[:0]
+	for _, v := range s {
+		if !seen[v] {
+			seen[v] = true
+			out = append(out, v)
+		}
+	}
+	return out
+}
+
+func init() {
+	addFilterFlags(permuteCmd)
+	permuteCmd.Flags().IntVar(&permuteMax, "max", 100000, "Maximum number of candidates (0 = no limit)")
+	permuteCmd.Flags().IntVar(&permuteLearned, "learned", 50, "Number of most frequent subdomain labels used as words")
+	permuteCmd.Flags().StringVar(&permuteWordFile, "words", "", "File of extra words, one per line")
+	permuteCmd.Flags().BoolVar(&permuteNoEnv, "no-env", false, "Do not use the built-in environment words")
+	rootCmd.AddCommand(permuteCmd)
+}
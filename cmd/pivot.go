@@ -0,0 +1,129 @@
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"slices"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/itsmeashim/rdb/config"
+	"github.com/itsmeashim/rdb/db"
+	"github.com/itsmeashim/rdb/models"
+	"github.com/jackc/pgx/v5"
+	"github.com/spf13/cobra"
+)
+
+var pivotBy []string
+
+// pivotResult is the set of records sharing one attribute value
+type pivotResult struct {
+	Attribute string             `json:"attribute"`
+	Value     string             `json:"value"`
+	Count     int64              `json:"count"`
+	Records   []models.HTTPXData `json:"records"`
+}
+
+var pivotCmd = &cobra.Command{
+	Use:   "pivot <record-id|url>",
+	Short: "Find records sharing a favicon, JARM, body hash, title or IP",
+	Long: `List every other URL sharing an attribute with the given record, with the
+number of URLs per value to show how common it is.
+
+Favicon and JARM hashes are stored when httpx runs with -favicon and -jarm;
+body hashes need -irr or -hash sha256.
+
+Examples:
+  rdb pivot https://login.example.com --by favicon,jarm
+  rdb pivot 1234 --by body-hash,title --program myprogram`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		for _, attr := range pivotBy {
+			if !slices.Contains(db.PivotAttributes, attr) {
+				return fmt.Errorf("invalid --by %q (valid: %s)", attr, strings.Join(db.PivotAttributes, ", "))
+			}
+		}
+
+		cfg, err := config.Load()
+		if err != nil {
+			return fmt.Errorf("failed to load config: %w", err)
+		}
+
+		if err := db.Init(cfg); err != nil {
+			return err
+		}
+		defer db.Close()
+
+		ctx := context.Background()
+		record, err := db.GetRecord(ctx, args[0])
+		if err == pgx.ErrNoRows {
+			return fmt.Errorf("no record found for %s", args[0])
+		}
+		if err != nil {
+			return fmt.Errorf("failed to load record: %w", err)
+		}
+
+		var results []pivotResult
+		for _, attr := range pivotBy {
+			for _, value := range pivotValues(record, attr) {
+				records, count, err := db.Pivot(ctx, attr, value, filterProgram, record.URL, limit)
+				if err != nil {
+					return fmt.Errorf("failed to pivot on %s: %w", attr, err)
+				}
+				results = append(results, pivotResult{Attribute: attr, Value: value, Count: count, Records: records})
+			}
+		}
+
+		if outputJSON {
+			encoder := json.NewEncoder(os.Stdout)
+			for _, r := range results {
+				encoder.Encode(r)
+			}
+			return nil
+		}
+
+		if len(results) == 0 {
+			fmt.Println("record has none of the requested attributes")
+			return nil
+		}
+
+		w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+		for _, r := range results {
+			fmt.Fprintf(w, "%s %s (%d other URLs)\n", r.Attribute, truncate(r.Value, 70), r.Count)
+			for _, d := range r.Records {
+				fmt.Fprintf(w, "  %s\t%d\t%s\t%s\n", d.URL, d.StatusCode, truncate(d.Title, 30), d.Program)
+			}
+		}
+		w.Flush()
+		return nil
+	},
+}
+
+// pivotValues returns the values of attr on a record; records can have
+// several IPs.
+func pivotValues(d models.HTTPXData, attr string) []string {
+	var values []string
+	switch attr {
+	case "favicon":
+		values = []string{d.Favicon}
+	case "jarm":
+		values = []string{d.Jarm}
+	case "body-hash":
+		values = []string{d.BodyHash}
+	case "title":
+		values = []string{d.Title}
+	case "ip":
+		values = slices.Clone(d.A)
+	}
+	return slices.DeleteFunc(values, func(v string) bool { return v == "" })
+}
+
+func init() {
+	pivotCmd.Flags().StringSliceVar(&pivotBy, "by", []string{"favicon", "jarm", "body-hash", "title", "ip"}, "Attributes to pivot on (favicon, jarm, body-hash, title, ip)")
+	pivotCmd.Flags().StringVar(&filterProgram, "program", "", "Only pivot within this program (default: across all programs)")
+	pivotCmd.Flags().IntVarP(&limit, "limit", "n", 0, "Limit records listed per value (0 = all)")
+	pivotCmd.Flags().BoolVarP(&outputJSON, "json", "j", false, "Output as JSON")
+	rootCmd.AddCommand(pivotCmd)
+}
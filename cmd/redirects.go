@@ -0,0 +1,129 @@
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/itsmeashim/rdb/config"
+	"github.com/itsmeashim/rdb/db"
+	"github.com/spf13/cobra"
+)
+
+var (
+	redirectsByURL        bool
+	redirectsSchemeSwitch bool
+)
+
+var redirectsCmd = &cobra.Command{
+	Use:   "redirects",
+	Short: "Group redirecting records by final destination",
+	Long: `List one line per redirect destination host with the hosts redirecting to
+it, most common first. Destinations on another registrable domain are marked
+external; they show where a program funnels to (SSO providers, parked pages)
+and are where open-redirect candidates hide.
+
+The destination is httpx's final_url when redirects were followed
+(-follow-redirects), else the last Location of the redirect chain
+(-include-chain), else the Location header.
+
+Supports the same filters as list.
+
+Examples:
+  rdb redirects --program myprogram
+  rdb redirects --external-redirect --by-url
+  rdb redirects --scheme-switch          # hosts redirecting to themselves on another scheme
+  rdb list --redirects-to okta.com`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		cfg, err := config.Load()
+		if err != nil {
+			return fmt.Errorf("failed to load config: %w", err)
+		}
+
+		if err := db.Init(cfg); err != nil {
+			return err
+		}
+		defer db.Close()
+
+		opts, err := listOptions()
+		if err != nil {
+			return err
+		}
+
+		ctx := context.Background()
+		if redirectsSchemeSwitch {
+			results, err := db.ListSchemeRedirects(ctx, opts)
+			if err != nil {
+				return fmt.Errorf("failed to query redirects: %w", err)
+			}
+			if outputJSON {
+				encoder := json.NewEncoder(os.Stdout)
+				for _, r := range results {
+					encoder.Encode(r)
+				}
+				return nil
+			}
+			if len(results) == 0 {
+				fmt.Println("no redirects found")
+				return nil
+			}
+			w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+			for _, r := range results {
+				fmt.Fprintf(w, "%s\t%s\t->\t%s\t%s\n", r.Hostname, r.URL, r.Target, r.Program)
+			}
+			w.Flush()
+			return nil
+		}
+
+		results, err := db.ListRedirects(ctx, opts, redirectsByURL)
+		if err != nil {
+			return fmt.Errorf("failed to query redirects: %w", err)
+		}
+
+		if outputJSON {
+			encoder := json.NewEncoder(os.Stdout)
+			for _, r := range results {
+				encoder.Encode(r)
+			}
+			return nil
+		}
+
+		if len(results) == 0 {
+			fmt.Println("no redirects found")
+			return nil
+		}
+
+		if separator != "" {
+			for _, r := range results {
+				fmt.Printf("%s%s%t%s%d%s%s%s%s\n", r.Destination, separator, r.External, separator,
+					len(r.Hosts), separator, strings.Join(r.Hosts, ","), separator, strings.Join(r.Programs, ","))
+			}
+			return nil
+		}
+
+		w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+		for _, r := range results {
+			flag := ""
+			if r.External {
+				flag = "external"
+			}
+			fmt.Fprintf(w, "%s\t%s\t%d hosts\t%s\t%s\n",
+				truncate(r.Destination, 60), flag, len(r.Hosts), truncate(strings.Join(r.Hosts, ","), 60), strings.Join(r.Programs, ","))
+		}
+		w.Flush()
+		return nil
+	},
+}
+
+func init() {
+	addFilterFlags(redirectsCmd)
+	redirectsCmd.Flags().BoolVar(&redirectsByURL, "by-url", false, "Group by full destination URL instead of destination host")
+	redirectsCmd.Flags().BoolVar(&redirectsSchemeSwitch, "scheme-switch", false, "List hosts redirecting to themselves on another scheme")
+	redirectsCmd.Flags().IntVarP(&limit, "limit", "n", 0, "Limit number of results (0 = all)")
+	redirectsCmd.Flags().BoolVarP(&outputJSON, "json", "j", false, "Output as JSON")
+	redirectsCmd.Flags().StringVarP(&separator, "sep", "s", "", "Field separator for piping (e.g., ',' or '|')")
+	rootCmd.AddCommand(redirectsCmd)
+}
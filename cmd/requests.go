@@ -0,0 +1,168 @@
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"os"
+	"sort"
+	"strconv"
+	"text/tabwriter"
+	"time"
+
+	"github.com/itsmeashim/rdb/config"
+	"github.com/itsmeashim/rdb/db"
+	"github.com/itsmeashim/rdb/models"
+	"github.com/spf13/cobra"
+)
+
+var requestsCmd = &cobra.Command{
+	Use:   "requests",
+	Short: "Browse captured request/response pairs",
+	Long: `Browse the traffic imported with 'rdb import --format har'. Each pair is
+linked to the latest stored record of its host.
+
+Examples:
+  rdb requests list --program myprogram --host api.example.com --method POST
+  rdb requests list --content-type json --status 200
+  rdb requests show 42`,
+}
+
+var requestsListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List captured requests",
+	RunE: func(cmd *cobra.Command, args []string) error {
+		cfg, err := config.Load()
+		if err != nil {
+			return fmt.Errorf("failed to load config: %w", err)
+		}
+
+		if err := db.Init(cfg); err != nil {
+			return err
+		}
+		defer db.Close()
+
+		requests, err := db.ListRequests(context.Background(), db.RequestOptions{
+			Program:     filterProgram,
+			Host:        filterHost,
+			Path:        filterPath,
+			Method:      filterMethod,
+			StatusCode:  filterStatusCode,
+			ContentType: filterContentType,
+			Limit:       limit,
+		})
+		if err != nil {
+			return fmt.Errorf("failed to query requests: %w", err)
+		}
+
+		if outputJSON {
+			encoder := json.NewEncoder(os.Stdout)
+			for _, r := range requests {
+				encoder.Encode(r)
+			}
+			return nil
+		}
+
+		if len(requests) == 0 {
+			fmt.Println("no requests found")
+			return nil
+		}
+
+		if separator != "" {
+			for _, r := range requests {
+				fmt.Printf("%d%s%s%s%s%s%d%s%s%s%s\n",
+					r.ID, separator, r.Method, separator, r.URL, separator, r.StatusCode, separator,
+					r.ContentType, separator, r.Program)
+			}
+			return nil
+		}
+
+		w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+		for _, r := range requests {
+			fmt.Fprintf(w, "%d\t%s\t%s\t%s\t%d\t%s\t%s\n",
+				r.ID, r.StartedAt.Format(time.DateTime), r.Method, truncate(r.URL, 80), r.StatusCode, r.ContentType, r.Program)
+		}
+		w.Flush()
+		return nil
+	},
+}
+
+var requestsShowCmd = &cobra.Command{
+	Use:   "show <request-id>",
+	Short: "Print a captured request and its response",
+	Args:  cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		id, err := strconv.ParseInt(args[0], 10, 64)
+		if err != nil {
+			return fmt.Errorf("invalid request id %q", args[0])
+		}
+
+		cfg, err := config.Load()
+		if err != nil {
+			return fmt.Errorf("failed to load config: %w", err)
+		}
+
+		if err := db.Init(cfg); err != nil {
+			return err
+		}
+		defer db.Close()
+
+		r, err := db.GetRequest(context.Background(), id)
+		if err != nil {
+			return err
+		}
+
+		if outputJSON {
+			return json.NewEncoder(os.Stdout).Encode(r)
+		}
+
+		target := r.Path
+		if u, err := url.Parse(r.URL); err == nil {
+			target = u.RequestURI()
+		}
+		fmt.Printf("%s %s HTTP/1.1\n", r.Method, target)
+		printHeaders(r.RequestHeaders)
+		fmt.Println()
+		if r.RequestBody != "" {
+			fmt.Println(r.RequestBody)
+			fmt.Println()
+		}
+
+		fmt.Printf("HTTP/1.1 %d\n", r.StatusCode)
+		printHeaders(r.ResponseHeaders)
+		fmt.Println()
+		if r.ResponseBody != "" {
+			fmt.Println(r.ResponseBody)
+		}
+		return nil
+	},
+}
+
+func printHeaders(h models.Headers) {
+	names := make([]string, 0, len(h))
+	for name := range h {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		fmt.Printf("%s: %v\n", name, h[name])
+	}
+}
+
+func init() {
+	requestsListCmd.Flags().StringVar(&filterProgram, "program", "", "Filter by program name")
+	requestsListCmd.Flags().StringVar(&filterHost, "host", "", "Filter by hostname (partial match)")
+	requestsListCmd.Flags().StringVar(&filterPath, "path", "", "Filter by path (partial match)")
+	requestsListCmd.Flags().StringVar(&filterMethod, "method", "", "Filter by method (exact)")
+	requestsListCmd.Flags().IntVar(&filterStatusCode, "status", 0, "Filter by response status code (exact)")
+	requestsListCmd.Flags().StringVar(&filterContentType, "content-type", "", "Filter by response content-type (partial match)")
+	requestsListCmd.Flags().IntVarP(&limit, "limit", "n", 0, "Limit number of results (0 = all)")
+	requestsListCmd.Flags().BoolVarP(&outputJSON, "json", "j", false, "Output as JSON")
+	requestsListCmd.Flags().StringVarP(&separator, "sep", "s", "", "Field separator for piping (e.g., ',' or '|')")
+
+	requestsShowCmd.Flags().BoolVarP(&outputJSON, "json", "j", false, "Output as JSON")
+
+	requestsCmd.AddCommand(requestsListCmd, requestsShowCmd)
+	rootCmd.AddCommand(requestsCmd)
+}
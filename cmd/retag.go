@@ -0,0 +1,96 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"slices"
+
+	"github.com/itsmeashim/rdb/config"
+	"github.com/itsmeashim/rdb/db"
+	"github.com/itsmeashim/rdb/label"
+	"github.com/itsmeashim/rdb/models"
+	"github.com/spf13/cobra"
+)
+
+var retagCmd = &cobra.Command{
+	Use:   "retag",
+	Short: "Re-apply labeling rules to stored records",
+	Long: `Recompute the labels of stored records with the built-in rules and the
+rules file, e.g. after editing the rules. Records are labeled at store time
+with the same rules.
+
+A rule attaches its labels when all of its conditions match. Conditions are
+case-insensitive regular expressions on title, tech (any entry), webserver,
+path, content_type and host, a list of status codes, and headers (an empty
+pattern only requires the header to be present):
+
+  rules:
+    - name: keycloak
+      labels: [login-panel, sso]
+      title: '^sign in to .+'
+      path: '^/auth/realms/'
+    - name: internal-staging
+      labels: [staging]
+      host: '\.int\.example\.com$'
+      headers:
+        x-env: 'stag'
+
+A rule with the same name as a built-in rule replaces it. Built-in labels:
+login-panel, jenkins, ci, grafana, monitoring, admin-console, staging,
+waf-block.
+
+Examples:
+  rdb retag --rules labels.yaml
+  rdb retag --program myprogram
+  rdb list --label login-panel --program myprogram`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		cfg, err := config.Load()
+		if err != nil {
+			return fmt.Errorf("failed to load config: %w", err)
+		}
+
+		if labelRulesFile == "" {
+			labelRulesFile = cfg.LabelRules
+		}
+		rules, err := label.Rules(labelRulesFile)
+		if err != nil {
+			return err
+		}
+
+		if err := db.Init(cfg); err != nil {
+			return err
+		}
+		defer db.Close()
+
+		opts, err := listOptions()
+		if err != nil {
+			return err
+		}
+
+		ctx := context.Background()
+		records, err := db.List(ctx, opts)
+		if err != nil {
+			return fmt.Errorf("failed to query records: %w", err)
+		}
+
+		changed := map[int64]models.StringArray{}
+		for i := range records {
+			labels := label.Apply(rules, &records[i])
+			if !slices.Equal(labels, records[i].Labels) {
+				changed[records[i].ID] = labels
+			}
+		}
+		if err := db.SetLabels(ctx, changed); err != nil {
+			return fmt.Errorf("failed to update labels: %w", err)
+		}
+
+		fmt.Printf("checked %d records, relabeled %d\n", len(records), len(changed))
+		return nil
+	},
+}
+
+func init() {
+	addFilterFlags(retagCmd)
+	retagCmd.Flags().StringVar(&labelRulesFile, "rules", "", "YAML file of labeling rules (default: label_rules from config)")
+	rootCmd.AddCommand(retagCmd)
+}
@@ -5,23 +5,59 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"io"
 	"os"
 
 	"github.com/spf13/cobra"
 	"github.com/itsmeashim/rdb/config"
 	"github.com/itsmeashim/rdb/db"
+	"github.com/itsmeashim/rdb/domain"
+	"github.com/itsmeashim/rdb/label"
 	"github.com/itsmeashim/rdb/models"
 )
 
 var (
 	program  string
 	platform string
+	emitNew  bool
+	emitAll  bool
+	newBy    string
+	noNotify bool
+
+	detectWildcards bool
+	labelRulesFile  string
+
+	fullScan  bool
+	goneAfter int
 )
 
 var storeCmd = &cobra.Command{
 	Use:   "store",
 	Short: "Store httpx JSON data from stdin",
-	Long:  `Reads httpx JSON output from stdin (piped) and stores it in the database.`,
+	Long: `Reads httpx JSON output from stdin (piped) and stores it in the database.
+
+With --emit-new, every input line whose URL (or host, see --new-by) was not
+already stored for the program is written to stdout, like anew:
+  httpx -l targets.txt -json | rdb store -p myprogram --emit-new | notify
+
+With --emit-all, every input line is passed through to stdout like tee.
+When emitting, the summary is written to stderr.
+
+Records are labeled with the built-in rules and the rules file from
+'rdb config --label-rules' (or --label-rules), see 'rdb retag'.
+
+With --detect-wildcards, wildcard detection (see 'rdb wildcards detect') is
+run for the program after storing. It is off by default because detection
+regroups every stored record of the program, which is wasted work for the
+small, frequent stores of a monitoring pipeline; enable it for brute-force
+runs.
+
+With --full-scan, the input is taken as a complete scan of the program:
+stored URLs it does not contain are counted as missing, and URLs missing
+from --gone-after consecutive full scans are marked gone. Gone assets are
+hidden from list and the other filtered commands unless --include-gone is
+given, and come back as soon as a scan sees them again:
+  httpx -l targets.txt -json | rdb store -p myprogram --full-scan --gone-after 3`,
 	RunE: func(cmd *cobra.Command, args []string) error {
 		cfg, err := config.Load()
 		if err != nil {
@@ -39,21 +75,59 @@ var storeCmd = &cobra.Command{
 		if platform == "" {
 			platform = cfg.DefaultPlatform
 		}
+		if newBy != "url" && newBy != "host" {
+			return fmt.Errorf("invalid --new-by %q: must be url or host", newBy)
+		}
+		if goneAfter < 1 {
+			return fmt.Errorf("invalid --gone-after %d: must be at least 1", goneAfter)
+		}
+
+		if labelRulesFile == "" {
+			labelRulesFile = cfg.LabelRules
+		}
+		rules, err := label.Rules(labelRulesFile)
+		if err != nil {
+			return err
+		}
 
 		stat, _ := os.Stdin.Stat()
 		if (stat.Mode() & os.ModeCharDevice) != 0 {
 			return fmt.Errorf("no input provided. Pipe httpx JSON output to this command")
 		}
 
-		scanner := bufio.NewScanner(os.Stdin)
-		buf := make([]byte, 0, 64*1024)
-		scanner.Buffer(buf, 1024*1024)
+		reader := bufio.NewReaderSize(os.Stdin, 64*1024)
+		maxLine := lineLimit(cfg.MaxBodySize)
+		var tee io.Writer
+		if emitAll {
+			// Tee mode passes every line through, stored or not.
+			tee = os.Stdout
+		}
 
 		count := 0
 		ctx := context.Background()
+		detectChanges := !noNotify && len(cfg.Notifications.Rules) > 0
+		var changes []models.Change
+
+		var scan models.Scan
+		if fullScan {
+			scan, err = db.StartScan(ctx, program, "httpx")
+			if err != nil {
+				return fmt.Errorf("failed to start scan: %w", err)
+			}
+		}
 
-		for scanner.Scan() {
-			line := scanner.Bytes()
+		for {
+			line, tooLong, err := readLine(reader, maxLine, tee)
+			if err == io.EOF {
+				break
+			}
+			if err != nil {
+				return fmt.Errorf("error reading input: %w", err)
+			}
+			if tooLong {
+				fmt.Fprintf(os.Stderr, "warning: skipping input line longer than %d bytes\n", maxLine)
+				continue
+			}
 			if len(line) == 0 {
 				continue
 			}
@@ -67,18 +141,65 @@ var storeCmd = &cobra.Command{
 			data.Program = program
 			data.Platform = platform
 
+			isNew := false
+			if emitNew {
+				exists, err := db.Exists(ctx, program, newBy, &data)
+				if err != nil {
+					fmt.Fprintf(os.Stderr, "warning: failed to check record: %v\n", err)
+				}
+				isNew = err == nil && !exists
+			}
+
+			var recordChanges []models.Change
+			if detectChanges {
+				recordChanges, err = db.Changes(ctx, &data)
+				if err != nil {
+					fmt.Fprintf(os.Stderr, "warning: failed to detect changes: %v\n", err)
+				}
+			}
+
+			data.Hostname = domain.Hostname(data.URL, data.Input, data.Host)
+			data.Labels = label.Apply(rules, &data)
+
 			if err := db.Insert(ctx, &data); err != nil {
 				fmt.Fprintf(os.Stderr, "warning: failed to insert: %v\n", err)
 				continue
 			}
 			count++
+			changes = append(changes, recordChanges...)
+
+			if isNew && !emitAll {
+				os.Stdout.Write(append(line, '\n'))
+			}
+		}
+
+		summary := fmt.Sprintf("stored %d records", count)
+		if fullScan {
+			if err := db.FinishScan(ctx, &scan, goneAfter); err != nil {
+				return fmt.Errorf("failed to finish scan: %w", err)
+			}
+			summary += fmt.Sprintf(", %d assets missing, %d marked gone", scan.Missing, scan.Gone)
+		}
+
+		if err := db.LinkRequests(ctx, program); err != nil {
+			fmt.Fprintf(os.Stderr, "warning: failed to link requests: %v\n", err)
 		}
 
-		if err := scanner.Err(); err != nil {
-			return fmt.Errorf("error reading input: %w", err)
+		if detectWildcards {
+			if _, err := db.DetectWildcards(ctx, program, clusterTolerance, wildcardMinHosts); err != nil {
+				fmt.Fprintf(os.Stderr, "warning: failed to detect wildcards: %v\n", err)
+			}
+		}
+
+		if detectChanges {
+			dispatchNotifications(ctx, cfg.Notifications, changes)
 		}
 
-		fmt.Printf("stored %d records\n", count)
+		if emitNew || emitAll {
+			fmt.Fprintln(os.Stderr, summary)
+		} else {
+			fmt.Println(summary)
+		}
 		return nil
 	},
 }
@@ -86,5 +207,14 @@ var storeCmd = &cobra.Command{
 func init() {
 	storeCmd.Flags().StringVarP(&program, "program", "p", "", "Program name (e.g., bugcrowd-program)")
 	storeCmd.Flags().StringVar(&platform, "platform", "", "Platform name (e.g., hackerone, bugcrowd)")
+	storeCmd.Flags().BoolVar(&emitNew, "emit-new", false, "Write input lines not already stored for the program to stdout")
+	storeCmd.Flags().BoolVar(&emitAll, "emit-all", false, "Write every input line to stdout (tee mode)")
+	storeCmd.Flags().StringVar(&newBy, "new-by", "url", "What makes a line new with --emit-new (url, host)")
+	storeCmd.Flags().BoolVar(&noNotify, "no-notify", false, "Do not send change notifications for this run")
+	storeCmd.Flags().StringVar(&labelRulesFile, "label-rules", "", "YAML file of labeling rules (default: label_rules from config)")
+	storeCmd.Flags().BoolVar(&detectWildcards, "detect-wildcards", false, "Run wildcard detection for the program after storing")
+	storeCmd.Flags().IntVar(&wildcardMinHosts, "wildcard-min-hosts", 5, "Minimum number of sibling subdomains answering alike for --detect-wildcards")
+	storeCmd.Flags().BoolVar(&fullScan, "full-scan", false, "Treat the input as a complete scan of the program and track assets missing from it")
+	storeCmd.Flags().IntVar(&goneAfter, "gone-after", 3, "Consecutive full scans an asset must be missing from before it is marked gone")
 	rootCmd.AddCommand(storeCmd)
 }
@@ -0,0 +1,101 @@
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/itsmeashim/rdb/config"
+	"github.com/itsmeashim/rdb/db"
+	"github.com/spf13/cobra"
+)
+
+var (
+	subdomainSource    string
+	subdomainNotProbed bool
+	subdomainNames     bool
+)
+
+var subdomainsCmd = &cobra.Command{
+	Use:   "subdomains",
+	Short: "List subdomains known from certificate transparency and passive DNS",
+	Long: `List the names imported with 'rdb import --format crtsh' or
+'--format pdns-json', with the sources they were seen in and when.
+
+With --not-probed, only names without any stored record are listed: the
+subdomains to probe next.
+
+Examples:
+  rdb subdomains --program myprogram --root-domain example.com
+  rdb subdomains --program myprogram --not-probed --names | httpx -json | rdb store -p myprogram`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		cfg, err := config.Load()
+		if err != nil {
+			return fmt.Errorf("failed to load config: %w", err)
+		}
+
+		if err := db.Init(cfg); err != nil {
+			return err
+		}
+		defer db.Close()
+
+		subdomains, err := db.KnownSubdomains(context.Background(), db.SubdomainOptions{
+			Program:    filterProgram,
+			RootDomain: filterRootDomain,
+			Source:     subdomainSource,
+			NotProbed:  subdomainNotProbed,
+			Limit:      limit,
+		})
+		if err != nil {
+			return fmt.Errorf("failed to query subdomains: %w", err)
+		}
+
+		if subdomainNames {
+			for _, s := range subdomains {
+				fmt.Println(s.Hostname)
+			}
+			return nil
+		}
+
+		if outputJSON {
+			encoder := json.NewEncoder(os.Stdout)
+			for _, s := range subdomains {
+				encoder.Encode(s)
+			}
+			return nil
+		}
+
+		if len(subdomains) == 0 {
+			fmt.Println("no subdomains found")
+			return nil
+		}
+
+		w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+		for _, s := range subdomains {
+			probed := "not probed"
+			if s.Probed {
+				probed = "probed"
+			}
+			fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\t%s\n",
+				s.Hostname, strings.Join(s.Sources, ","), s.FirstSeen.Format(time.DateOnly),
+				s.LastSeen.Format(time.DateOnly), probed, s.Program)
+		}
+		w.Flush()
+		return nil
+	},
+}
+
+func init() {
+	subdomainsCmd.Flags().StringVar(&filterProgram, "program", "", "Filter by program name")
+	subdomainsCmd.Flags().StringVar(&filterRootDomain, "root-domain", "", "Filter by registrable domain, e.g. example.co.uk (exact)")
+	subdomainsCmd.Flags().StringVar(&subdomainSource, "source", "", "Only names seen in this source (crtsh, pdns-json, ...)")
+	subdomainsCmd.Flags().BoolVar(&subdomainNotProbed, "not-probed", false, "Only names with no stored record")
+	subdomainsCmd.Flags().BoolVar(&subdomainNames, "names", false, "Only output hostnames")
+	subdomainsCmd.Flags().IntVarP(&limit, "limit", "n", 0, "Limit number of results (0 = all)")
+	subdomainsCmd.Flags().BoolVarP(&outputJSON, "json", "j", false, "Output as JSON")
+	rootCmd.AddCommand(subdomainsCmd)
+}
@@ -0,0 +1,260 @@
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"os"
+	"path/filepath"
+	"text/tabwriter"
+	"time"
+
+	"github.com/itsmeashim/rdb/config"
+	"github.com/itsmeashim/rdb/db"
+	"github.com/itsmeashim/rdb/models"
+	"github.com/itsmeashim/rdb/takeover"
+	"github.com/jackc/pgx/v5"
+	"github.com/spf13/cobra"
+)
+
+var (
+	takeoverFingerprints string
+	takeoverOnlyNew      bool
+	takeoverService      string
+	takeoverIncludeGone  bool
+	takeoverSourceURL    string
+)
+
+var takeoverCmd = &cobra.Command{
+	Use:   "takeover",
+	Short: "Find subdomain takeover candidates from stored CNAMEs",
+	Long: `Check the latest record of every stored URL whose CNAME (httpx -cname)
+points at a third-party service against the can-i-take-over-xyz fingerprints:
+the body or title of an unclaimed resource (stored with httpx -irr), or a
+CNAME without A records for services that only dangle as NXDOMAIN.
+
+Results are recorded, so each run reports every candidate as new or seen,
+and previously found candidates of the checked hosts that are no longer
+found as gone.
+
+The built-in fingerprint list is used unless 'rdb takeover update' has
+downloaded a newer one, or --fingerprints points at a local file.
+
+Examples:
+  httpx -l subs.txt -json -cname -irr | rdb store -p myprogram
+  rdb takeover --program myprogram
+  rdb takeover --new --json | notify
+  rdb takeover list --include-gone`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		fps, err := loadFingerprints()
+		if err != nil {
+			return err
+		}
+
+		cfg, err := config.Load()
+		if err != nil {
+			return fmt.Errorf("failed to load config: %w", err)
+		}
+
+		if err := db.Init(cfg); err != nil {
+			return err
+		}
+		defer db.Close()
+
+		opts, err := listOptions()
+		if err != nil {
+			return err
+		}
+		opts.Limit = 0
+
+		ctx := context.Background()
+		records, err := db.LatestRecords(ctx, opts)
+		if err != nil {
+			return fmt.Errorf("failed to query records: %w", err)
+		}
+
+		var checked []models.Target
+		var found []models.TakeoverCandidate
+		seenHost := map[models.Target]bool{}
+		seenCandidate := map[string]bool{}
+		for i := range records {
+			r := &records[i]
+			host := models.Target{Program: r.Program, Scope: db.ScopeHost, Value: r.Hostname}
+			if !seenHost[host] {
+				seenHost[host] = true
+				checked = append(checked, host)
+			}
+
+			fp, cname := takeover.Service(fps, r.CNAME)
+			if fp == nil {
+				continue
+			}
+			var body []byte
+			if r.BodyHash != "" {
+				if body, err = db.LoadBody(ctx, r.BodyHash); err != nil && err != pgx.ErrNoRows {
+					fmt.Fprintf(os.Stderr, "warning: failed to load body of %s: %v\n", r.URL, err)
+				}
+			}
+			evidence, confidence := takeover.Check(fp, r, body)
+			key := r.Program + "\x00" + r.Hostname + "\x00" + fp.Service
+			if evidence == "" || seenCandidate[key] {
+				continue
+			}
+			seenCandidate[key] = true
+			found = append(found, models.TakeoverCandidate{
+				Program:    r.Program,
+				Hostname:   r.Hostname,
+				URL:        r.URL,
+				Service:    fp.Service,
+				CNAME:      cname,
+				Evidence:   evidence,
+				Confidence: confidence,
+			})
+		}
+
+		results, err := db.SaveTakeoverRun(ctx, checked, found)
+		if err != nil {
+			return fmt.Errorf("failed to record candidates: %w", err)
+		}
+		if takeoverOnlyNew {
+			n := 0
+			for _, c := range results {
+				if c.State == db.TakeoverNew {
+					results[n] = c
+					n++
+				}
+			}
+			results = results[:n]
+		}
+
+		return printTakeoverCandidates(results)
+	},
+}
+
+var takeoverListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List recorded takeover candidates",
+	RunE: func(cmd *cobra.Command, args []string) error {
+		cfg, err := config.Load()
+		if err != nil {
+			return fmt.Errorf("failed to load config: %w", err)
+		}
+
+		if err := db.Init(cfg); err != nil {
+			return err
+		}
+		defer db.Close()
+
+		results, err := db.ListTakeoverCandidates(context.Background(), filterProgram, takeoverService, takeoverIncludeGone, limit)
+		if err != nil {
+			return fmt.Errorf("failed to query candidates: %w", err)
+		}
+		return printTakeoverCandidates(results)
+	},
+}
+
+var takeoverUpdateCmd = &cobra.Command{
+	Use:   "update",
+	Short: "Download the latest can-i-take-over-xyz fingerprints",
+	RunE: func(cmd *cobra.Command, args []string) error {
+		path, err := fingerprintsPath()
+		if err != nil {
+			return err
+		}
+
+		client := &http.Client{Timeout: 30 * time.Second}
+		resp, err := client.Get(takeoverSourceURL)
+		if err != nil {
+			return fmt.Errorf("failed to download fingerprints: %w", err)
+		}
+		defer resp.Body.Close()
+		if resp.StatusCode != http.StatusOK {
+			return fmt.Errorf("failed to download fingerprints: %s", resp.Status)
+		}
+
+		fps, err := takeover.Parse(resp.Body)
+		if err != nil {
+			return err
+		}
+		data, err := json.MarshalIndent(fps, "", "  ")
+		if err != nil {
+			return err
+		}
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			return err
+		}
+		if err := os.WriteFile(path, data, 0644); err != nil {
+			return err
+		}
+		fmt.Printf("saved %d fingerprints to %s\n", len(fps), path)
+		return nil
+	},
+}
+
+// fingerprintsPath is where 'rdb takeover update' saves the fingerprints,
+// next to the config file.
+func fingerprintsPath() (string, error) {
+	path, err := config.ConfigPath()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(filepath.Dir(path), "takeover-fingerprints.json"), nil
+}
+
+// loadFingerprints loads --fingerprints, else the downloaded list, else the
+// built-in one.
+func loadFingerprints() ([]takeover.Fingerprint, error) {
+	path := takeoverFingerprints
+	if path == "" {
+		downloaded, err := fingerprintsPath()
+		if err != nil {
+			return nil, err
+		}
+		if _, err := os.Stat(downloaded); err == nil {
+			path = downloaded
+		}
+	}
+	return takeover.Load(path)
+}
+
+func printTakeoverCandidates(results []models.TakeoverCandidate) error {
+	if outputJSON {
+		encoder := json.NewEncoder(os.Stdout)
+		for _, r := range results {
+			encoder.Encode(r)
+		}
+		return nil
+	}
+
+	if len(results) == 0 {
+		fmt.Println("no takeover candidates found")
+		return nil
+	}
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	for _, r := range results {
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\t%s\t%s\n",
+			r.State, r.Hostname, r.Service, r.CNAME, r.Confidence, truncate(r.Evidence, 60), r.Program)
+	}
+	w.Flush()
+	return nil
+}
+
+func init() {
+	addFilterFlags(takeoverCmd)
+	takeoverCmd.Flags().StringVar(&takeoverFingerprints, "fingerprints", "", "can-i-take-over-xyz fingerprints.json file (default: downloaded or built-in list)")
+	takeoverCmd.Flags().BoolVar(&takeoverOnlyNew, "new", false, "Only report candidates not found by the previous run")
+	takeoverCmd.Flags().BoolVarP(&outputJSON, "json", "j", false, "Output as JSON")
+
+	takeoverListCmd.Flags().StringVar(&filterProgram, "program", "", "Filter by program name")
+	takeoverListCmd.Flags().StringVar(&takeoverService, "service", "", "Filter by service (partial match)")
+	takeoverListCmd.Flags().BoolVar(&takeoverIncludeGone, "include-gone", false, "Include candidates no longer found")
+	takeoverListCmd.Flags().IntVarP(&limit, "limit", "n", 0, "Limit number of results (0 = all)")
+	takeoverListCmd.Flags().BoolVarP(&outputJSON, "json", "j", false, "Output as JSON")
+
+	takeoverUpdateCmd.Flags().StringVar(&takeoverSourceURL, "url", takeover.SourceURL, "Fingerprint list to download")
+
+	takeoverCmd.AddCommand(takeoverListCmd, takeoverUpdateCmd)
+	rootCmd.AddCommand(takeoverCmd)
+}
@@ -0,0 +1,214 @@
+package cmd
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net"
+	"net/url"
+	"os"
+	"slices"
+	"strings"
+
+	"github.com/itsmeashim/rdb/config"
+	"github.com/itsmeashim/rdb/db"
+	"github.com/itsmeashim/rdb/models"
+	"github.com/itsmeashim/rdb/scope"
+	"github.com/spf13/cobra"
+)
+
+var (
+	targetsFor     string
+	targetsNoScope bool
+)
+
+var targetsFormats = []string{"nuclei", "ffuf", "naabu", "hostport", "nmap", "burp-scope"}
+
+var targetsCmd = &cobra.Command{
+	Use:   "targets",
+	Short: "Export stored assets as input for other tools",
+	Long: `Print the assets of the records matching the filters in the shape a tool
+expects, deduplicated:
+
+  nuclei      URLs
+  ffuf        base URLs (scheme://host[:port]), for -u URL/FUZZ
+  naabu       hostnames
+  hostport    host:port pairs
+  nmap        IP addresses
+  burp-scope  Burp Suite target scope JSON (advanced mode)
+
+Hosts outside the program's scope (the "scopes" section of the config file)
+are left out unless --no-scope is set.
+
+Examples:
+  rdb targets --for nuclei --program myprogram | nuclei -t exposures/
+  rdb targets --for ffuf --status 200 > urls.txt
+  rdb targets --for nmap --program myprogram | nmap -iL - -sV
+  rdb targets --for burp-scope --program myprogram > scope.json`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if !slices.Contains(targetsFormats, targetsFor) {
+			return fmt.Errorf("invalid --for %q (valid: %s)", targetsFor, strings.Join(targetsFormats, ", "))
+		}
+
+		cfg, err := config.Load()
+		if err != nil {
+			return fmt.Errorf("failed to load config: %w", err)
+		}
+
+		if err := db.Init(cfg); err != nil {
+			return err
+		}
+		defer db.Close()
+
+		opts, err := listOptions()
+		if err != nil {
+			return err
+		}
+		opts.Limit = 0
+
+		records, err := db.LatestRecords(context.Background(), opts)
+		if err != nil {
+			return fmt.Errorf("failed to query records: %w", err)
+		}
+
+		inScope := records[:0]
+		for _, r := range records {
+			if targetsNoScope || scope.Allows(cfg.Scopes[r.Program], r.Hostname) {
+				inScope = append(inScope, r)
+			}
+		}
+
+		if targetsFor == "burp-scope" {
+			return writeBurpScope(inScope, cfg.Scopes, targetsNoScope)
+		}
+
+		out := bufio.NewWriter(os.Stdout)
+		defer out.Flush()
+		seen := map[string]bool{}
+		emit := func(s string) {
+			if s != "" && !seen[s] {
+				seen[s] = true
+				fmt.Fprintln(out, s)
+			}
+		}
+
+		for _, r := range inScope {
+			switch targetsFor {
+			case "nuclei":
+				emit(r.URL)
+			case "ffuf":
+				emit(baseURL(r.URL))
+			case "naabu":
+				emit(r.Hostname)
+			case "hostport":
+				if port := recordPort(r); port != "" {
+					emit(net.JoinHostPort(r.Hostname, port))
+				}
+			case "nmap":
+				if net.ParseIP(r.Hostname) != nil {
+					emit(r.Hostname)
+				}
+				for _, ip := range r.A {
+					// IPs listed out of scope stay out even for in-scope hosts.
+					if targetsNoScope || scope.Allows(config.Scope{Out: cfg.Scopes[r.Program].Out}, ip) {
+						emit(ip)
+					}
+				}
+			}
+		}
+		return nil
+	},
+}
+
+// baseURL returns the scheme and host of rawURL, e.g. https://example.com:8443.
+func baseURL(rawURL string) string {
+	u, err := url.Parse(rawURL)
+	if err != nil || u.Host == "" {
+		return ""
+	}
+	return u.Scheme + "://" + u.Host
+}
+
+// recordPort returns the port of a record, defaulting to the scheme's.
+func recordPort(r models.HTTPXData) string {
+	if r.Port != "" {
+		return r.Port
+	}
+	switch r.Scheme {
+	case "https":
+		return "443"
+	case "http":
+		return "80"
+	}
+	return ""
+}
+
+type burpScopeRule struct {
+	Enabled  bool   `json:"enabled"`
+	File     string `json:"file,omitempty"`
+	Host     string `json:"host"`
+	Port     string `json:"port,omitempty"`
+	Protocol string `json:"protocol"`
+}
+
+// writeBurpScope writes a Burp Suite target scope including every stored
+// scheme, host and port, and excluding the out-of-scope patterns of the
+// programs involved.
+func writeBurpScope(records []models.HTTPXData, scopes map[string]config.Scope, noScope bool) error {
+	include := []burpScopeRule{}
+	exclude := []burpScopeRule{}
+	seen := map[string]bool{}
+	var programs []string
+
+	for _, r := range records {
+		if !slices.Contains(programs, r.Program) {
+			programs = append(programs, r.Program)
+		}
+		protocol := r.Scheme
+		if protocol != "http" && protocol != "https" {
+			protocol = "any"
+		}
+		rule := burpScopeRule{Enabled: true, Host: scope.Regex(r.Hostname), Protocol: protocol, File: "^/.*"}
+		if port := recordPort(r); port != "" {
+			rule.Port = "^" + port + "$"
+		}
+		key := rule.Protocol + " " + rule.Host + " " + rule.Port
+		if rule.Host != "" && !seen[key] {
+			seen[key] = true
+			include = append(include, rule)
+		}
+	}
+
+	if !noScope {
+		for _, program := range programs {
+			for _, p := range scopes[program].Out {
+				if re := scope.Regex(p); re != "" && !seen[re] {
+					seen[re] = true
+					exclude = append(exclude, burpScopeRule{Enabled: true, Host: re, Protocol: "any"})
+				}
+			}
+		}
+	}
+
+	doc := map[string]interface{}{
+		"target": map[string]interface{}{
+			"scope": map[string]interface{}{
+				"advanced_mode": true,
+				"include":       include,
+				"exclude":       exclude,
+			},
+		},
+	}
+	encoder := json.NewEncoder(os.Stdout)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(doc)
+}
+
+func init() {
+	addFilterFlags(targetsCmd)
+	targetsCmd.Flags().StringVar(&targetsFor, "for", "", "Output format ("+strings.Join(targetsFormats, ", ")+")")
+	targetsCmd.Flags().BoolVar(&targetsNoScope, "no-scope", false, "Ignore the program scopes from the config file")
+	targetsCmd.MarkFlagRequired("for")
+	rootCmd.AddCommand(targetsCmd)
+}
@@ -0,0 +1,101 @@
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/itsmeashim/rdb/config"
+	"github.com/itsmeashim/rdb/db"
+	"github.com/itsmeashim/rdb/models"
+	"github.com/spf13/cobra"
+)
+
+var techName string
+
+var techCmd = &cobra.Command{
+	Use:   "tech",
+	Short: "List technologies with their version spread and host counts",
+	Long: `List each detected technology per program with the versions seen and the
+number of distinct hosts running it.
+
+Examples:
+  rdb tech --program myprogram
+  rdb tech --name nginx
+  rdb tech --tech-version 'PHP<8'`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		cfg, err := config.Load()
+		if err != nil {
+			return fmt.Errorf("failed to load config: %w", err)
+		}
+
+		if err := db.Init(cfg); err != nil {
+			return err
+		}
+		defer db.Close()
+
+		opts, err := listOptions()
+		if err != nil {
+			return err
+		}
+
+		results, err := db.ListTechnologies(context.Background(), opts, techName)
+		if err != nil {
+			return fmt.Errorf("failed to query technologies: %w", err)
+		}
+
+		if outputJSON {
+			encoder := json.NewEncoder(os.Stdout)
+			for _, r := range results {
+				encoder.Encode(r)
+			}
+			return nil
+		}
+
+		if len(results) == 0 {
+			fmt.Println("no technologies found")
+			return nil
+		}
+
+		if separator != "" {
+			for _, r := range results {
+				fmt.Printf("%s%s%s%s%s%s%d%s%s\n", r.Name, separator, r.Category, separator,
+					r.Program, separator, r.Hosts, separator, formatVersions(r.Versions))
+			}
+			return nil
+		}
+
+		w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+		for _, r := range results {
+			fmt.Fprintf(w, "%s\t%s\t%s\t%d\t%s\n",
+				r.Name, r.Category, r.Program, r.Hosts, truncate(formatVersions(r.Versions), 60))
+		}
+		w.Flush()
+		return nil
+	},
+}
+
+// formatVersions renders a version spread as "1.18.0(12),1.20.1(3)".
+func formatVersions(versions []models.VersionCount) string {
+	parts := make([]string, len(versions))
+	for i, v := range versions {
+		version := v.Version
+		if version == "" {
+			version = "unknown"
+		}
+		parts[i] = fmt.Sprintf("%s(%d)", version, v.Hosts)
+	}
+	return strings.Join(parts, ",")
+}
+
+func init() {
+	addFilterFlags(techCmd)
+	techCmd.Flags().StringVar(&techName, "name", "", "Only show technologies whose name contains this (partial match)")
+	techCmd.Flags().IntVarP(&limit, "limit", "n", 0, "Limit number of results (0 = all)")
+	techCmd.Flags().BoolVarP(&outputJSON, "json", "j", false, "Output as JSON")
+	techCmd.Flags().StringVarP(&separator, "sep", "s", "", "Field separator for piping (e.g., ',' or '|')")
+	rootCmd.AddCommand(techCmd)
+}
@@ -0,0 +1,104 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/itsmeashim/rdb/config"
+	"github.com/itsmeashim/rdb/db"
+	"github.com/spf13/cobra"
+)
+
+var treeCmd = &cobra.Command{
+	Use:   "tree",
+	Short: "Print the subdomain hierarchy of a registrable domain",
+	Long: `Print the subdomains stored under a registrable domain as a tree, with the
+number of records at each level (including everything below it).
+
+Example:
+  rdb tree --root-domain example.co.uk --program myprogram`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if filterRootDomain == "" {
+			return fmt.Errorf("--root-domain is required")
+		}
+
+		cfg, err := config.Load()
+		if err != nil {
+			return fmt.Errorf("failed to load config: %w", err)
+		}
+
+		if err := db.Init(cfg); err != nil {
+			return err
+		}
+		defer db.Close()
+
+		opts, err := listOptions()
+		if err != nil {
+			return err
+		}
+
+		counts, err := db.ListSubdomains(context.Background(), opts)
+		if err != nil {
+			return fmt.Errorf("failed to query subdomains: %w", err)
+		}
+
+		if len(counts) == 0 {
+			fmt.Println("no records found")
+			return nil
+		}
+
+		root := &treeNode{label: strings.ToLower(filterRootDomain), children: map[string]*treeNode{}}
+		for _, c := range counts {
+			node := root
+			node.total += c.Records
+			if c.Subdomain == "" {
+				continue
+			}
+			labels := strings.Split(c.Subdomain, ".")
+			for i := len(labels) - 1; i >= 0; i-- {
+				child, ok := node.children[labels[i]]
+				if !ok {
+					child = &treeNode{label: labels[i], children: map[string]*treeNode{}}
+					node.children[labels[i]] = child
+				}
+				child.total += c.Records
+				node = child
+			}
+		}
+
+		fmt.Printf("%s (%d)\n", root.label, root.total)
+		root.print("")
+		return nil
+	},
+}
+
+type treeNode struct {
+	label    string
+	total    int64
+	children map[string]*treeNode
+}
+
+func (n *treeNode) print(prefix string) {
+	labels := make([]string, 0, len(n.children))
+	for label := range n.children {
+		labels = append(labels, label)
+	}
+	sort.Strings(labels)
+
+	for i, label := range labels {
+		child := n.children[label]
+		branch, indent := "├── ", "│   "
+		if i == len(labels)-1 {
+			branch, indent = "└── ", "    "
+		}
+		fmt.Printf("%s%s%s (%d)\n", prefix, branch, child.label, child.total)
+		child.print(prefix + indent)
+	}
+}
+
+func init() {
+	addFilterFlags(treeCmd)
+	rootCmd.AddCommand(treeCmd)
+}
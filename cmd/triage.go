@@ -0,0 +1,75 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/itsmeashim/rdb/config"
+	"github.com/itsmeashim/rdb/db"
+	"github.com/itsmeashim/rdb/models"
+	"github.com/spf13/cobra"
+)
+
+var (
+	assetStatus string
+
+	// assetStatuses is the work progress vocabulary of hosts and URLs;
+	// extracted values use findingStatuses instead.
+	assetStatuses = []string{"new", "in-progress", "done", "ignored"}
+)
+
+var triageCmd = &cobra.Command{
+	Use:   "triage <host|id|url>...",
+	Short: "Set the triage status of hosts or URLs",
+	Long: `Set the triage status of a host, or of a single URL when given a record id
+or URL. A URL's own status wins over its host's; assets without a status are
+new. Statuses are kept across re-stores and can be listed with
+'rdb list --status-triage <status>'.
+
+Statuses: new, in-progress, done, ignored.
+
+Examples:
+  rdb triage admin.example.com --status in-progress
+  rdb triage 1234 https://example.com/login --status done
+  rdb list --program myprogram --status-triage new`,
+	Args: cobra.MinimumNArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if !slices.Contains(assetStatuses, assetStatus) {
+			return fmt.Errorf("invalid --status %q (valid: %s)", assetStatus, strings.Join(assetStatuses, ", "))
+		}
+
+		cfg, err := config.Load()
+		if err != nil {
+			return fmt.Errorf("failed to load config: %w", err)
+		}
+
+		if err := db.Init(cfg); err != nil {
+			return err
+		}
+		defer db.Close()
+
+		ctx := context.Background()
+		var targets []models.Target
+		for _, a := range args {
+			t, err := db.ResolveTargets(ctx, a, filterProgram)
+			if err != nil {
+				return err
+			}
+			targets = append(targets, t...)
+		}
+
+		if err := db.SetTriageStatus(ctx, targets, assetStatus); err != nil {
+			return fmt.Errorf("failed to update status: %w", err)
+		}
+		fmt.Printf("updated %d targets\n", len(targets))
+		return nil
+	},
+}
+
+func init() {
+	triageCmd.Flags().StringVar(&assetStatus, "status", "", "New asset status ("+strings.Join(assetStatuses, ", ")+")")
+	triageCmd.Flags().StringVar(&filterProgram, "program", "", "Only update hosts within this program (default: every program they were seen in)")
+	rootCmd.AddCommand(triageCmd)
+}
@@ -0,0 +1,208 @@
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"text/tabwriter"
+
+	"github.com/itsmeashim/rdb/config"
+	"github.com/itsmeashim/rdb/db"
+	"github.com/itsmeashim/rdb/models"
+	"github.com/itsmeashim/rdb/vuln"
+	"github.com/spf13/cobra"
+)
+
+var (
+	vulnFeedFile string
+	vulnSource   string
+	vulnMinCVSS  float64
+	vulnHighOnly bool
+)
+
+var vulnsCmd = &cobra.Command{
+	Use:   "vulns",
+	Short: "Match stored technology versions against a local CVE feed",
+	Long: `Load a downloaded CVE feed into the database and match it against the
+technology versions detected by httpx. Works fully offline.
+
+Examples:
+  rdb vulns sync --file nvdcve-1.1-2023.json
+  rdb vulns sync --file osv-nginx.json
+  rdb vulns match --program myprogram --min-cvss 7`,
+}
+
+var vulnsSyncCmd = &cobra.Command{
+	Use:   "sync",
+	Short: "Load an NVD (1.1 or 2.0) or OSV JSON dump",
+	Long: `Load a local NVD JSON 1.1 feed, NVD JSON 2.0 feed/API response or OSV JSON
+dump into the database. Re-syncing a file replaces the ranges previously loaded
+from it.`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if vulnFeedFile == "" {
+			return fmt.Errorf("--file is required")
+		}
+
+		f, err := os.Open(vulnFeedFile)
+		if err != nil {
+			return fmt.Errorf("failed to open feed: %w", err)
+		}
+		defer f.Close()
+
+		source := vulnSource
+		if source == "" {
+			source = filepath.Base(vulnFeedFile)
+		}
+
+		ranges, err := vuln.Parse(f, source)
+		if err != nil {
+			return err
+		}
+
+		cfg, err := config.Load()
+		if err != nil {
+			return fmt.Errorf("failed to load config: %w", err)
+		}
+
+		if err := db.Init(cfg); err != nil {
+			return err
+		}
+		defer db.Close()
+
+		n, err := db.ReplaceVulnerabilities(context.Background(), source, ranges)
+		if err != nil {
+			return fmt.Errorf("failed to store feed: %w", err)
+		}
+
+		fmt.Printf("loaded %d affected version ranges from %s\n", n, source)
+		return nil
+	},
+}
+
+var vulnsMatchCmd = &cobra.Command{
+	Use:   "match",
+	Short: "List hosts running technology versions with known CVEs",
+	RunE: func(cmd *cobra.Command, args []string) error {
+		cfg, err := config.Load()
+		if err != nil {
+			return fmt.Errorf("failed to load config: %w", err)
+		}
+
+		if err := db.Init(cfg); err != nil {
+			return err
+		}
+		defer db.Close()
+
+		opts, err := listOptions()
+		if err != nil {
+			return err
+		}
+
+		ctx := context.Background()
+		instances, err := db.ListTechInstances(ctx, opts)
+		if err != nil {
+			return fmt.Errorf("failed to query technologies: %w", err)
+		}
+
+		products := map[string]bool{}
+		for _, t := range instances {
+			_, product := vuln.Product(t.Name)
+			products[product] = true
+		}
+		names := make([]string, 0, len(products))
+		for p := range products {
+			names = append(names, p)
+		}
+
+		ranges, err := db.ListVulnRanges(ctx, names)
+		if err != nil {
+			return fmt.Errorf("failed to query vulnerabilities: %w", err)
+		}
+		byProduct := map[string][]vuln.Range{}
+		for _, r := range ranges {
+			byProduct[r.Product] = append(byProduct[r.Product], r)
+		}
+
+		var matches []models.VulnMatch
+		for _, t := range instances {
+			vendor, product := vuln.Product(t.Name)
+			seen := map[string]bool{}
+			for _, r := range byProduct[product] {
+				confidence, ok := r.VendorMatch(vendor)
+				if !ok || (vulnHighOnly && confidence != vuln.ConfidenceHigh) {
+					continue
+				}
+				if seen[r.CVE] || r.CVSS < vulnMinCVSS || !r.Contains(t.VersionParts) {
+					continue
+				}
+				seen[r.CVE] = true
+				matches = append(matches, models.VulnMatch{
+					Host:       t.Host,
+					Technology: t.Name,
+					Version:    t.Version,
+					CVE:        r.CVE,
+					CVSS:       r.CVSS,
+					Confidence: confidence,
+					Program:    t.Program,
+				})
+			}
+		}
+
+		sort.SliceStable(matches, func(i, j int) bool {
+			if matches[i].CVSS != matches[j].CVSS {
+				return matches[i].CVSS > matches[j].CVSS
+			}
+			return matches[i].Host < matches[j].Host
+		})
+		if limit > 0 && len(matches) > limit {
+			matches = matches[:limit]
+		}
+
+		if outputJSON {
+			encoder := json.NewEncoder(os.Stdout)
+			for _, m := range matches {
+				encoder.Encode(m)
+			}
+			return nil
+		}
+
+		if len(matches) == 0 {
+			fmt.Println("no vulnerable versions found")
+			return nil
+		}
+
+		if separator != "" {
+			for _, m := range matches {
+				fmt.Printf("%s%s%s%s%s%s%s%s%.1f%s%s%s%s\n", m.Host, separator, m.Technology, separator,
+					m.Version, separator, m.CVE, separator, m.CVSS, separator, m.Confidence, separator, m.Program)
+			}
+			return nil
+		}
+
+		w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+		for _, m := range matches {
+			fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%.1f\t%s\t%s\n",
+				m.Host, m.Technology, m.Version, m.CVE, m.CVSS, m.Confidence, m.Program)
+		}
+		w.Flush()
+		return nil
+	},
+}
+
+func init() {
+	vulnsSyncCmd.Flags().StringVarP(&vulnFeedFile, "file", "f", "", "Path to an NVD or OSV JSON dump")
+	vulnsSyncCmd.Flags().StringVar(&vulnSource, "source", "", "Source name to record (default: file name)")
+
+	addFilterFlags(vulnsMatchCmd)
+	vulnsMatchCmd.Flags().Float64Var(&vulnMinCVSS, "min-cvss", 0, "Only show CVEs with at least this CVSS score")
+	vulnsMatchCmd.Flags().BoolVar(&vulnHighOnly, "high-confidence", false, "Only show matches where the CPE vendor is confirmed")
+	vulnsMatchCmd.Flags().IntVarP(&limit, "limit", "n", 0, "Limit number of results (0 = all)")
+	vulnsMatchCmd.Flags().BoolVarP(&outputJSON, "json", "j", false, "Output as JSON")
+	vulnsMatchCmd.Flags().StringVarP(&separator, "sep", "s", "", "Field separator for piping (e.g., ',' or '|')")
+
+	vulnsCmd.AddCommand(vulnsSyncCmd, vulnsMatchCmd)
+	rootCmd.AddCommand(vulnsCmd)
+}
@@ -0,0 +1,106 @@
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"text/tabwriter"
+
+	"github.com/itsmeashim/rdb/config"
+	"github.com/itsmeashim/rdb/db"
+	"github.com/spf13/cobra"
+)
+
+var wildcardMinHosts int
+
+var wildcardsCmd = &cobra.Command{
+	Use:   "wildcards",
+	Short: "List parent domains detected as wildcard or catch-all",
+	Long: `List the parent domains whose subdomains all resolve to the same IPs and
+return the same status and content length, which usually means wildcard DNS
+or a catch-all virtual host. Run 'rdb wildcards detect' (or 'rdb store
+--detect-wildcards') to refresh the results.
+
+Records of those subdomains are marked and can be hidden with
+'rdb list --exclude-wildcards'.`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		cfg, err := config.Load()
+		if err != nil {
+			return fmt.Errorf("failed to load config: %w", err)
+		}
+
+		if err := db.Init(cfg); err != nil {
+			return err
+		}
+		defer db.Close()
+
+		results, err := db.ListWildcards(context.Background(), filterProgram)
+		if err != nil {
+			return fmt.Errorf("failed to query wildcards: %w", err)
+		}
+
+		if outputJSON {
+			encoder := json.NewEncoder(os.Stdout)
+			for _, r := range results {
+				encoder.Encode(r)
+			}
+			return nil
+		}
+
+		if len(results) == 0 {
+			fmt.Println("no wildcards found")
+			return nil
+		}
+
+		w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+		for _, r := range results {
+			fmt.Fprintf(w, "*.%s\t%d\t%d hosts\t%s\t%s\n", r.Parent, r.StatusCode, r.Hosts, truncate(r.IPs, 50), r.Program)
+		}
+		w.Flush()
+		return nil
+	},
+}
+
+var wildcardsDetectCmd = &cobra.Command{
+	Use:   "detect",
+	Short: "Detect wildcard and catch-all parent domains",
+	Long: `Group stored subdomains by parent domain, IP set, status code and content
+length. A parent domain with at least --min-hosts subdomains in one group is
+flagged as wildcard and the records of that group are marked. Previous
+results for the program are replaced.
+
+Examples:
+  rdb wildcards detect --program myprogram
+  rdb wildcards detect --min-hosts 10 --tolerance 0.05`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		cfg, err := config.Load()
+		if err != nil {
+			return fmt.Errorf("failed to load config: %w", err)
+		}
+
+		if err := db.Init(cfg); err != nil {
+			return err
+		}
+		defer db.Close()
+
+		n, err := db.DetectWildcards(context.Background(), filterProgram, clusterTolerance, wildcardMinHosts)
+		if err != nil {
+			return fmt.Errorf("failed to detect wildcards: %w", err)
+		}
+		fmt.Printf("detected %d wildcard groups\n", n)
+		return nil
+	},
+}
+
+func init() {
+	wildcardsCmd.Flags().StringVar(&filterProgram, "program", "", "Filter by program name")
+	wildcardsCmd.Flags().BoolVarP(&outputJSON, "json", "j", false, "Output as JSON")
+
+	wildcardsDetectCmd.Flags().StringVar(&filterProgram, "program", "", "Only detect within this program (default all)")
+	wildcardsDetectCmd.Flags().IntVar(&wildcardMinHosts, "min-hosts", 5, "Minimum number of sibling subdomains answering alike")
+	wildcardsDetectCmd.Flags().Float64Var(&clusterTolerance, "tolerance", 0.1, "Relative content length tolerance")
+
+	wildcardsCmd.AddCommand(wildcardsDetectCmd)
+	rootCmd.AddCommand(wildcardsCmd)
+}
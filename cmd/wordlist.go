@@ -0,0 +1,110 @@
+package cmd
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"slices"
+	"strings"
+
+	"github.com/itsmeashim/rdb/config"
+	"github.com/itsmeashim/rdb/db"
+	"github.com/itsmeashim/rdb/wordlist"
+	"github.com/spf13/cobra"
+)
+
+var (
+	wordlistKind     string
+	wordlistMinCount int
+	wordlistMinLen   int
+	wordlistMaxLen   int
+	wordlistCounts   bool
+)
+
+var wordlistCmd = &cobra.Command{
+	Use:   "wordlist",
+	Short: "Generate a wordlist from stored data",
+	Long: `Mine stored records for words and print them by descending frequency,
+one per line, ready for ffuf or puredns.
+
+Kinds:
+  subdomains    labels of subdomains (api, dev, ...)
+  paths         URL path segments, skipping numeric ids, UUIDs and hashes
+  params        query parameter names
+  title-words   words in page titles
+
+Counts are the number of distinct subdomains, URLs or titles a word was
+found in. Supports the same filters as list.
+
+Examples:
+  rdb wordlist --kind subdomains --program myprogram > subs.txt
+  rdb wordlist --kind paths --min-count 2 --min-len 3 | ffuf -w - -u https://target/FUZZ
+  rdb wordlist --kind params --counts`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if !slices.Contains(wordlist.Kinds, wordlistKind) {
+			return fmt.Errorf("invalid --kind %q (valid: %s)", wordlistKind, strings.Join(wordlist.Kinds, ", "))
+		}
+
+		cfg, err := config.Load()
+		if err != nil {
+			return fmt.Errorf("failed to load config: %w", err)
+		}
+
+		if err := db.Init(cfg); err != nil {
+			return err
+		}
+		defer db.Close()
+
+		opts, err := listOptions()
+		if err != nil {
+			return err
+		}
+		opts.Limit = 0
+
+		values, err := db.WordlistSources(context.Background(), opts, wordlistKind)
+		if err != nil {
+			return fmt.Errorf("failed to query records: %w", err)
+		}
+
+		entries := wordlist.Rank(wordlistKind, values, wordlist.Options{
+			MinCount: wordlistMinCount,
+			MinLen:   wordlistMinLen,
+			MaxLen:   wordlistMaxLen,
+			Limit:    limit,
+		})
+
+		if outputJSON {
+			encoder := json.NewEncoder(os.Stdout)
+			for _, e := range entries {
+				encoder.Encode(e)
+			}
+			return nil
+		}
+
+		out := bufio.NewWriter(os.Stdout)
+		defer out.Flush()
+		for _, e := range entries {
+			if wordlistCounts {
+				fmt.Fprintf(out, "%d\t%s\n", e.Count, e.Word)
+			} else {
+				fmt.Fprintln(out, e.Word)
+			}
+		}
+		return nil
+	},
+}
+
+func init() {
+	addFilterFlags(wordlistCmd)
+	wordlistCmd.Flags().StringVar(&wordlistKind, "kind", "", "Wordlist kind ("+strings.Join(wordlist.Kinds, ", ")+")")
+	wordlistCmd.Flags().IntVar(&wordlistMinCount, "min-count", 1, "Only words found at least this many times")
+	wordlistCmd.Flags().IntVar(&wordlistMinLen, "min-len", 1, "Minimum word length")
+	wordlistCmd.Flags().IntVar(&wordlistMaxLen, "max-len", 0, "Maximum word length (0 = no limit)")
+	wordlistCmd.Flags().BoolVar(&wordlistCounts, "counts", false, "Prefix each word with its count")
+	wordlistCmd.Flags().IntVarP(&limit, "limit", "n", 0, "Limit number of words (0 = all)")
+	wordlistCmd.Flags().BoolVarP(&outputJSON, "json", "j", false, "Output as JSON")
+	wordlistCmd.MarkFlagRequired("kind")
+	rootCmd.AddCommand(wordlistCmd)
+}
@@ -11,6 +11,20 @@ type Config struct {
 	MaxConnections   int    `json:"max_connections"`
 	DefaultProgram   string `json:"default_program"`
 	DefaultPlatform  string `json:"default_platform"`
+	// User is the name recorded on notes and matched by "me"; defaults to
+	// the login name.
+	User string `json:"user"`
+	// MaxBodySize is the number of response body bytes stored per record;
+	// 0, the default, disables body storage.
+	MaxBodySize int `json:"max_body_size"`
+	// LabelRules is a YAML file of labeling rules applied in addition to the
+	// built-in ones.
+	LabelRules string `json:"label_rules"`
+
+	Notifications NotifyConfig `json:"notifications"`
+	Enrich        EnrichConfig `json:"enrich"`
+	// Scopes maps program names to their scope.
+	Scopes map[string]Scope `json:"scopes,omitempty"`
 }
 
 func DefaultConfig() *Config {
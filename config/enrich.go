@@ -0,0 +1,10 @@
+package config
+
+// EnrichConfig locates the local datasets used by 'rdb enrich'.
+type EnrichConfig struct {
+	// CloudRanges maps provider names (aws, gcp, azure, cloudflare, ...) to
+	// their published IP range files.
+	CloudRanges map[string]string `json:"cloud_ranges,omitempty"`
+	// ASNDB is an iptoasn TSV file or an ASN MMDB database.
+	ASNDB string `json:"asn_db,omitempty"`
+}
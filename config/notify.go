@@ -0,0 +1,33 @@
+package config
+
+// NotifyConfig configures the change notifications sent after rdb store.
+type NotifyConfig struct {
+	Webhooks []Webhook    `json:"webhooks,omitempty"`
+	Rules    []NotifyRule `json:"rules,omitempty"`
+	// MinIntervalSeconds rate limits each webhook; changes arriving sooner
+	// are queued and included in the next digest.
+	MinIntervalSeconds int `json:"min_interval_seconds,omitempty"`
+	// MaxEvents caps the number of changes listed in one digest message.
+	MaxEvents int `json:"max_events,omitempty"`
+}
+
+// Webhook is a notification destination. Type is one of webhook, slack,
+// discord or telegram; for telegram, URL is the bot sendMessage endpoint.
+type Webhook struct {
+	Name   string `json:"name"`
+	Type   string `json:"type"`
+	URL    string `json:"url"`
+	ChatID string `json:"chat_id,omitempty"`
+}
+
+// NotifyRule selects changes and the webhooks they are sent to. Empty
+// filters match everything; FromStatus and ToStatus only apply to
+// status_change events.
+type NotifyRule struct {
+	Name       string   `json:"name"`
+	Events     []string `json:"events,omitempty"`
+	Programs   []string `json:"programs,omitempty"`
+	FromStatus []int    `json:"from_status,omitempty"`
+	ToStatus   []int    `json:"to_status,omitempty"`
+	Webhooks   []string `json:"webhooks"`
+}
@@ -0,0 +1,9 @@
+package config
+
+// Scope lists the in-scope and out-of-scope targets of a program as
+// hostnames, "*.example.com" wildcards, IP addresses or CIDR ranges. An
+// empty In allows everything not listed in Out.
+type Scope struct {
+	In  []string `json:"in,omitempty"`
+	Out []string `json:"out,omitempty"`
+}
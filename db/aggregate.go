@@ -0,0 +1,102 @@
+package db
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/itsmeashim/rdb/models"
+	"github.com/jackc/pgx/v5"
+)
+
+// filteredCTE returns a "WITH f AS (...)" clause selecting the httpx_data rows
+// matching opts, so aggregate queries can share the list filters.
+func filteredCTE(opts ListOptions) (string, []interface{}) {
+	where, args := buildFilters(opts)
+	return "WITH f AS (SELECT * FROM httpx_data WHERE 1=1" + where + ")", args
+}
+
+func ListHosts(ctx context.Context, opts ListOptions) ([]models.HostSummary, error) {
+	cte, args := filteredCTE(opts)
+	query := cte + `
+		SELECT f.hostname,
+			COALESCE(array_agg(DISTINCT f.port ORDER BY f.port) FILTER (WHERE f.port <> ''), '{}'),
+			COALESCE(array_agg(DISTINCT f.scheme ORDER BY f.scheme) FILTER (WHERE f.scheme <> ''), '{}'),
+			COALESCE(array_agg(DISTINCT f.status_code ORDER BY f.status_code) FILTER (WHERE f.status_code <> 0), '{}'),
+			COALESCE((SELECT array_agg(DISTINCT t ORDER BY t) FROM f f2, jsonb_array_elements_text(f2.tech) t
+				WHERE f2.hostname = f.hostname), '{}'),
+			COALESCE((SELECT array_agg(DISTINCT ip ORDER BY ip) FROM f f2, jsonb_array_elements_text(f2.a) ip
+				WHERE f2.hostname = f.hostname), '{}'),
+			array_agg(DISTINCT f.program ORDER BY f.program),
+			array_agg(DISTINCT f.source ORDER BY f.source),
+			count(*)
+		FROM f
+		WHERE f.hostname <> ''
+		GROUP BY f.hostname
+		ORDER BY f.hostname`
+
+	if opts.Limit > 0 {
+		query += fmt.Sprintf(" LIMIT %d", opts.Limit)
+	}
+
+	rows, err := pool.Query(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	return pgx.CollectRows(rows, func(row pgx.CollectableRow) (models.HostSummary, error) {
+		var h models.HostSummary
+		err := row.Scan(&h.Host, &h.Ports, &h.Schemes, &h.StatusCodes, &h.Tech, &h.A, &h.Programs, &h.Sources, &h.Records)
+		return h, err
+	})
+}
+
+func ListIPs(ctx context.Context, opts ListOptions) ([]models.IPSummary, error) {
+	cte, args := filteredCTE(opts)
+	query := cte + `
+		SELECT ip,
+			array_agg(DISTINCT f.hostname ORDER BY f.hostname),
+			count(DISTINCT f.hostname) AS host_count,
+			array_agg(DISTINCT f.program ORDER BY f.program)
+		FROM f, jsonb_array_elements_text(f.a) ip
+		GROUP BY ip
+		ORDER BY host_count DESC, ip`
+
+	if opts.Limit > 0 {
+		query += fmt.Sprintf(" LIMIT %d", opts.Limit)
+	}
+
+	rows, err := pool.Query(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	return pgx.CollectRows(rows, func(row pgx.CollectableRow) (models.IPSummary, error) {
+		var s models.IPSummary
+		err := row.Scan(&s.IP, &s.Hosts, &s.HostCount, &s.Programs)
+		return s, err
+	})
+}
+
+func ListSubdomains(ctx context.Context, opts ListOptions) ([]models.SubdomainCount, error) {
+	cte, args := filteredCTE(opts)
+	query := cte + `
+		SELECT COALESCE(f.subdomain, ''), count(*)
+		FROM f
+		WHERE f.root_domain <> ''
+		GROUP BY 1
+		ORDER BY 1`
+
+	rows, err := pool.Query(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	return pgx.CollectRows(rows, func(row pgx.CollectableRow) (models.SubdomainCount, error) {
+		var s models.SubdomainCount
+		err := row.Scan(&s.Subdomain, &s.Records)
+		return s, err
+	})
+}
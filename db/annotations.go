@@ -0,0 +1,178 @@
+package db
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/itsmeashim/rdb/domain"
+	"github.com/itsmeashim/rdb/models"
+	"github.com/jackc/pgx/v5"
+)
+
+const annotationSchemaSQL = `
+CREATE TABLE IF NOT EXISTS annotations (
+    program TEXT NOT NULL,
+    scope TEXT NOT NULL,
+    target TEXT NOT NULL,
+    status TEXT,
+    assignee TEXT,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (program, scope, target)
+);
+
+CREATE TABLE IF NOT EXISTS notes (
+    id SERIAL PRIMARY KEY,
+    program TEXT NOT NULL,
+    scope TEXT NOT NULL,
+    target TEXT NOT NULL,
+    author TEXT NOT NULL DEFAULT '',
+    text TEXT NOT NULL,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_notes_target ON notes(program, scope, target);
+`
+
+// Annotation scopes.
+const (
+	ScopeURL  = "url"
+	ScopeHost = "host"
+)
+
+// annotationSQL returns the value of an annotation column for the current
+// httpx_data row. A URL's own annotation wins over its host's.
+func annotationSQL(column string) string {
+	return fmt.Sprintf(`(SELECT an.%s FROM annotations an
+		WHERE an.program = httpx_data.program AND an.%[1]s IS NOT NULL
+			AND ((an.scope = 'url' AND an.target = httpx_data.url) OR (an.scope = 'host' AND an.target = httpx_data.hostname))
+		ORDER BY an.scope = 'url' DESC LIMIT 1)`, column)
+}
+
+// ResolveTargets turns a record id or URL into the URL target of that record,
+// and anything else into the host target in every program the host was seen
+// in, or only in program when it is set.
+func ResolveTargets(ctx context.Context, arg, program string) ([]models.Target, error) {
+	if _, err := strconv.ParseInt(arg, 10, 64); err == nil || strings.Contains(arg, "://") {
+		r, err := GetRecord(ctx, arg)
+		if err != nil {
+			if err == pgx.ErrNoRows {
+				return nil, fmt.Errorf("no record found for %q", arg)
+			}
+			return nil, err
+		}
+		if program != "" && r.Program != program {
+			return nil, fmt.Errorf("record %q belongs to program %q", arg, r.Program)
+		}
+		return []models.Target{{Program: r.Program, Scope: ScopeURL, Value: r.URL}}, nil
+	}
+
+	host := strings.ToLower(arg)
+	rows, err := pool.Query(ctx, `
+		SELECT DISTINCT program FROM httpx_data
+		WHERE hostname = $1 AND ($2 = '' OR program = $2)
+		ORDER BY program`, host, program)
+	if err != nil {
+		return nil, err
+	}
+	programs, err := pgx.CollectRows(rows, pgx.RowTo[string])
+	if err != nil {
+		return nil, err
+	}
+	if len(programs) == 0 {
+		return nil, fmt.Errorf("no records found for host %q", arg)
+	}
+
+	targets := make([]models.Target, 0, len(programs))
+	for _, p := range programs {
+		targets = append(targets, models.Target{Program: p, Scope: ScopeHost, Value: host})
+	}
+	return targets, nil
+}
+
+// SetTriageStatus sets the triage status of targets.
+func SetTriageStatus(ctx context.Context, targets []models.Target, status string) error {
+	return upsertAnnotations(ctx, targets, "status", status)
+}
+
+// Assign sets the assignee of targets; an empty assignee clears it.
+func Assign(ctx context.Context, targets []models.Target, assignee string) error {
+	return upsertAnnotations(ctx, targets, "assignee", assignee)
+}
+
+func upsertAnnotations(ctx context.Context, targets []models.Target, column, value string) error {
+	batch := &pgx.Batch{}
+	for _, t := range targets {
+		batch.Queue(fmt.Sprintf(`
+			INSERT INTO annotations (program, scope, target, %[1]s) VALUES ($1, $2, $3, NULLIF($4, ''))
+			ON CONFLICT (program, scope, target) DO UPDATE
+			SET %[1]s = EXCLUDED.%[1]s, updated_at = CURRENT_TIMESTAMP`, column),
+			t.Program, t.Scope, t.Value, value)
+	}
+	return pool.SendBatch(ctx, batch).Close()
+}
+
+// AddNote stores a note on each target and returns the id of the last one.
+func AddNote(ctx context.Context, targets []models.Target, author, text string) (int64, error) {
+	var id int64
+	for _, t := range targets {
+		err := pool.QueryRow(ctx, `
+			INSERT INTO notes (program, scope, target, author, text) VALUES ($1, $2, $3, $4, $5)
+			RETURNING id`, t.Program, t.Scope, t.Value, author, text).Scan(&id)
+		if err != nil {
+			return 0, err
+		}
+	}
+	return id, nil
+}
+
+// DeleteNote removes a note by id and reports whether it existed.
+func DeleteNote(ctx context.Context, id int64) (bool, error) {
+	tag, err := pool.Exec(ctx, `DELETE FROM notes WHERE id = $1`, id)
+	return tag.RowsAffected() > 0, err
+}
+
+// NoteOptions filters ListNotes. Targets also match the notes of a URL's
+// host when a URL target is given.
+type NoteOptions struct {
+	Program string
+	Targets []models.Target
+	Author  string
+	Limit   int
+}
+
+func ListNotes(ctx context.Context, opts NoteOptions) ([]models.Note, error) {
+	query := `SELECT id, program, scope, target, author, text, created_at FROM notes WHERE ($1 = '' OR program = $1) AND ($2 = '' OR author = $2)`
+	args := []interface{}{opts.Program, opts.Author}
+
+	if len(opts.Targets) > 0 {
+		var conds []string
+		for _, t := range opts.Targets {
+			conds = append(conds, fmt.Sprintf("(program = $%d AND scope = $%d AND target = $%d)", len(args)+1, len(args)+2, len(args)+3))
+			args = append(args, t.Program, t.Scope, t.Value)
+			if t.Scope == ScopeURL {
+				conds = append(conds, fmt.Sprintf("(program = $%d AND scope = 'host' AND target = $%d)", len(args)+1, len(args)+2))
+				args = append(args, t.Program, domain.Hostname(t.Value))
+			}
+		}
+		query += " AND (" + strings.Join(conds, " OR ") + ")"
+	}
+
+	query += " ORDER BY created_at DESC, id DESC"
+	if opts.Limit > 0 {
+		query += fmt.Sprintf(" LIMIT %d", opts.Limit)
+	}
+
+	rows, err := pool.Query(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	return pgx.CollectRows(rows, func(row pgx.CollectableRow) (models.Note, error) {
+		var n models.Note
+		err := row.Scan(&n.ID, &n.Program, &n.Scope, &n.Value, &n.Author, &n.Text, &n.CreatedAt)
+		return n, err
+	})
+}
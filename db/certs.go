@@ -0,0 +1,176 @@
+package db
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/itsmeashim/rdb/models"
+	"github.com/jackc/pgx/v5"
+)
+
+const certSchemaSQL = `
+CREATE TABLE IF NOT EXISTS certificates (
+    id SERIAL PRIMARY KEY,
+    fingerprint TEXT NOT NULL UNIQUE,
+    subject_cn TEXT,
+    subject_dn TEXT,
+    sans TEXT[],
+    issuer_cn TEXT,
+    issuer_dn TEXT,
+    issuer_org TEXT,
+    serial TEXT,
+    not_before TIMESTAMPTZ,
+    not_after TIMESTAMPTZ,
+    self_signed BOOLEAN NOT NULL DEFAULT FALSE,
+    wildcard BOOLEAN NOT NULL DEFAULT FALSE,
+    first_seen TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    last_seen TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_certificates_not_after ON certificates(not_after);
+
+ALTER TABLE httpx_data ADD COLUMN IF NOT EXISTS certificate_id INT REFERENCES certificates(id);
+CREATE INDEX IF NOT EXISTS idx_certificate_id ON httpx_data(certificate_id);
+`
+
+// certHostMatches is true when the record hostname is covered by the
+// certificate common name or SANs, including single-label wildcards.
+const certHostMatches = `(h.hostname = c.subject_cn OR h.hostname = ANY(c.sans)
+	OR ('*' || substr(h.hostname, strpos(h.hostname, '.'))) = ANY(c.sans || c.subject_cn))`
+
+// upsertCertificate stores a certificate deduplicated by fingerprint and
+// returns its ID, or nil when the record has no certificate.
+func upsertCertificate(ctx context.Context, tx pgx.Tx, t *models.TLSData) (*int64, error) {
+	fp := t.FingerprintSHA256()
+	if fp == "" {
+		return nil, nil
+	}
+
+	var id int64
+	err := tx.QueryRow(ctx, `
+		INSERT INTO certificates (fingerprint, subject_cn, subject_dn, sans, issuer_cn, issuer_dn, issuer_org,
+			serial, not_before, not_after, self_signed, wildcard)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12)
+		ON CONFLICT (fingerprint) DO UPDATE SET last_seen = CURRENT_TIMESTAMP
+		RETURNING id`,
+		strings.ToLower(fp), strings.ToLower(t.SubjectCN), t.SubjectDN, lowerAll(t.SubjectAN), t.IssuerCN, t.IssuerDN,
+		strings.Join(t.IssuerOrg, ", "), t.Serial, parseTime(t.NotBefore), parseTime(t.NotAfter),
+		t.SelfSigned, t.Wildcard).Scan(&id)
+	if err != nil {
+		return nil, err
+	}
+	return &id, nil
+}
+
+func lowerAll(values []string) []string {
+	out := make([]string, len(values))
+	for i, v := range values {
+		out[i] = strings.ToLower(v)
+	}
+	return out
+}
+
+// parseTime parses an httpx timestamp, returning nil when empty or invalid.
+func parseTime(s string) *time.Time {
+	t, err := time.Parse(time.RFC3339, s)
+	if err != nil {
+		return nil
+	}
+	return &t
+}
+
+type CertOptions struct {
+	Program        string
+	SAN            string
+	Issuer         string
+	ExpiringWithin time.Duration
+	SelfSigned     bool
+	Expired        bool
+	MismatchedHost bool
+	Limit          int
+}
+
+// ListCertificates returns certificates with the hosts that served them.
+// With MismatchedHost only hosts not covered by the certificate are listed.
+func ListCertificates(ctx context.Context, opts CertOptions) ([]models.Certificate, error) {
+	query := `SELECT c.id, c.fingerprint, COALESCE(c.subject_cn, ''), COALESCE(c.sans, '{}'),
+		COALESCE(c.issuer_cn, ''), COALESCE(c.issuer_org, ''), c.not_before, c.not_after, c.self_signed,
+		array_agg(DISTINCT h.hostname), array_agg(DISTINCT h.program)
+		FROM certificates c JOIN httpx_data h ON h.certificate_id = c.id
+		WHERE 1=1`
+	args := []interface{}{}
+	argNum := 1
+
+	if opts.Program != "" {
+		query += fmt.Sprintf(" AND h.program = $%d", argNum)
+		args = append(args, opts.Program)
+		argNum++
+	}
+	if opts.SAN != "" {
+		query += fmt.Sprintf(" AND EXISTS (SELECT 1 FROM unnest(c.sans || c.subject_cn) san WHERE san ILIKE $%d)", argNum)
+		args = append(args, "%"+opts.SAN+"%")
+		argNum++
+	}
+	if opts.Issuer != "" {
+		query += fmt.Sprintf(" AND (c.issuer_cn ILIKE $%[1]d OR c.issuer_dn ILIKE $%[1]d OR c.issuer_org ILIKE $%[1]d)", argNum)
+		args = append(args, "%"+opts.Issuer+"%")
+		argNum++
+	}
+	if opts.ExpiringWithin > 0 {
+		query += fmt.Sprintf(" AND c.not_after BETWEEN now() AND now() + $%d::interval", argNum)
+		args = append(args, opts.ExpiringWithin)
+		argNum++
+	}
+	if opts.SelfSigned {
+		query += " AND c.self_signed"
+	}
+	if opts.Expired {
+		query += " AND c.not_after < now()"
+	}
+	if opts.MismatchedHost {
+		query += " AND h.hostname <> '' AND NOT " + certHostMatches
+	}
+
+	query += " GROUP BY c.id ORDER BY c.not_after NULLS LAST"
+	if opts.Limit > 0 {
+		query += fmt.Sprintf(" LIMIT %d", opts.Limit)
+	}
+
+	rows, err := pool.Query(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	return pgx.CollectRows(rows, func(row pgx.CollectableRow) (models.Certificate, error) {
+		var c models.Certificate
+		err := row.Scan(&c.ID, &c.Fingerprint, &c.SubjectCN, &c.SANs, &c.IssuerCN, &c.IssuerOrg,
+			&c.NotBefore, &c.NotAfter, &c.SelfSigned, &c.Hosts, &c.Programs)
+		return c, err
+	})
+}
+
+// SANCandidates returns certificate names that have no httpx_data record,
+// as subdomain candidates. Wildcard names are reduced to their parent
+// domain. With a program, only certificates served to it are considered.
+func SANCandidates(ctx context.Context, program string) ([]string, error) {
+	query := `
+		WITH names AS (
+			SELECT DISTINCT regexp_replace(name, '^\*\.', '') AS name
+			FROM certificates c
+			JOIN httpx_data h ON h.certificate_id = c.id,
+			unnest(c.sans || c.subject_cn) name
+			WHERE name LIKE '%.%' AND ($1 = '' OR h.program = $1)
+		)
+		SELECT name FROM names n
+		WHERE NOT EXISTS (SELECT 1 FROM httpx_data h WHERE h.hostname = n.name)
+		ORDER BY name`
+
+	rows, err := pool.Query(ctx, query, program)
+	if err != nil {
+		return nil, err
+	}
+	return pgx.CollectRows(rows, pgx.RowTo[string])
+}
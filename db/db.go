@@ -3,9 +3,12 @@ package db
 import (
 	"context"
 	"fmt"
+	"strings"
 
 	"github.com/itsmeashim/rdb/config"
+	"github.com/itsmeashim/rdb/domain"
 	"github.com/itsmeashim/rdb/models"
+	"github.com/itsmeashim/rdb/technology"
 	"github.com/jackc/pgx/v5"
 	"github.com/jackc/pgx/v5/pgxpool"
 )
@@ -43,6 +46,14 @@ CREATE INDEX IF NOT EXISTS idx_input ON httpx_data(input);
 CREATE INDEX IF NOT EXISTS idx_webserver ON httpx_data(webserver);
 CREATE INDEX IF NOT EXISTS idx_program ON httpx_data(program);
 CREATE INDEX IF NOT EXISTS idx_platform ON httpx_data(platform);
+
+ALTER TABLE httpx_data ADD COLUMN IF NOT EXISTS hostname TEXT;
+ALTER TABLE httpx_data ADD COLUMN IF NOT EXISTS root_domain TEXT;
+ALTER TABLE httpx_data ADD COLUMN IF NOT EXISTS subdomain TEXT;
+CREATE INDEX IF NOT EXISTS idx_hostname ON httpx_data(hostname);
+CREATE INDEX IF NOT EXISTS idx_root_domain ON httpx_data(root_domain);
+DROP INDEX IF EXISTS idx_hostname_pending;
+CREATE INDEX IF NOT EXISTS idx_root_domain_pending ON httpx_data(id) WHERE root_domain IS NULL;
 `
 
 func Init(cfg *config.Config) error {
@@ -56,6 +67,7 @@ func Init(cfg *config.Config) error {
 	}
 
 	poolCfg.MaxConns = int32(cfg.MaxConnections)
+	maxBodySize = cfg.MaxBodySize
 
 	pool, err = pgxpool.NewWithConfig(context.Background(), poolCfg)
 	if err != nil {
@@ -66,9 +78,23 @@ func Init(cfg *config.Config) error {
 		return fmt.Errorf("failed to connect to database: %w", err)
 	}
 
-	_, err = pool.Exec(context.Background(), createTableSQL)
-	if err != nil {
-		return fmt.Errorf("failed to create table: %w", err)
+	for _, schema := range []string{createTableSQL, techSchemaSQL, vulnSchemaSQL, notifySchemaSQL, responseSchemaSQL, extractionSchemaSQL, pivotSchemaSQL, certSchemaSQL, wildcardSchemaSQL, labelSchemaSQL, annotationSchemaSQL, takeoverSchemaSQL, redirectSchemaSQL, importSchemaSQL, requestSchemaSQL, subdomainSchemaSQL, dnsSchemaSQL, enrichSchemaSQL, livenessSchemaSQL} {
+		if _, err := pool.Exec(context.Background(), schema); err != nil {
+			return fmt.Errorf("failed to create table: %w", err)
+		}
+	}
+
+	if err := backfillDomains(context.Background()); err != nil {
+		return fmt.Errorf("failed to backfill root domains: %w", err)
+	}
+	if err := backfillTechnologies(context.Background()); err != nil {
+		return fmt.Errorf("failed to backfill technologies: %w", err)
+	}
+	if err := backfillRedirects(context.Background()); err != nil {
+		return fmt.Errorf("failed to backfill redirects: %w", err)
+	}
+	if err := backfillDNS(context.Background()); err != nil {
+		return fmt.Errorf("failed to backfill DNS history: %w", err)
 	}
 
 	return nil
@@ -81,46 +107,214 @@ func Close() {
 }
 
 func Insert(ctx context.Context, data *models.HTTPXData) error {
-	_, err := pool.Exec(ctx, `
+	data.Hostname = domain.Hostname(data.URL, data.Input, data.Host)
+	data.RootDomain, data.Subdomain = domain.Split(data.Hostname)
+	if data.Source == "" {
+		data.Source = "httpx"
+	}
+	redirectURL, redirectHost, redirectRoot := redirectColumns(data)
+	data.RedirectURL = redirectURL
+
+	tx, err := pool.Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback(ctx)
+
+	if data.BodyHash, err = storeBody(ctx, tx, data.Body, maxBodySize); err != nil {
+		return err
+	}
+	if data.BodyHash == "" {
+		data.BodyHash = data.Hash.BodySHA256()
+	}
+	// Older httpx releases emit "jarm", newer ones "jarm_hash".
+	if data.Jarm == "" {
+		data.Jarm = data.JarmHash
+	}
+	certID, err := upsertCertificate(ctx, tx, data.TLS)
+	if err != nil {
+		return err
+	}
+
+	err = tx.QueryRow(ctx, `
 		INSERT INTO httpx_data (
 			port, url, input, location, title, scheme, webserver,
 			content_type, method, host, path, time, a, tech,
-			words, lines, status_code, content_length, program, platform
-		) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16, $17, $18, $19, $20)
+			words, lines, status_code, content_length, program, platform,
+			hostname, root_domain, subdomain, headers, body_hash, favicon, jarm, certificate_id, labels, cname,
+			chain, redirect_url, redirect_host, redirect_root, source, tech_parsed, dns_recorded
+		) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16, $17, $18, $19, $20,
+			$21, $22, $23, $24, NULLIF($25, ''), NULLIF($26, ''), NULLIF($27, ''), $28, COALESCE($29, '[]'::jsonb), $30,
+			$31, $32, $33, $34, $35, TRUE, TRUE)
+		RETURNING id
 	`, data.Port, data.URL, data.Input, data.Location, data.Title, data.Scheme, data.Webserver,
 		data.ContentType, data.Method, data.Host, data.Path, data.Time, data.A, data.Tech,
-		data.Words, data.Lines, data.StatusCode, data.ContentLength, data.Program, data.Platform)
-	return err
+		data.Words, data.Lines, data.StatusCode, data.ContentLength, data.Program, data.Platform,
+		data.Hostname, data.RootDomain, data.Subdomain, data.Headers, data.BodyHash,
+		data.Favicon, data.Jarm, certID, data.Labels, data.CNAME,
+		data.Chain, redirectURL, redirectHost, redirectRoot, data.Source).Scan(&data.ID)
+	if err != nil {
+		return err
+	}
+
+	if err := insertTechnologies(ctx, tx, data.ID, data.Tech); err != nil {
+		return err
+	}
+	if err := recordDNS(ctx, tx, data); err != nil {
+		return err
+	}
+	if err := markSeen(ctx, tx, data); err != nil {
+		return err
+	}
+
+	return tx.Commit(ctx)
+}
+
+// backfillDomains computes hostname, root_domain and subdomain for records
+// without a root_domain, such as those stored before the columns existed or
+// given a hostname by an earlier migration. Hosts without a registrable
+// domain, such as IP addresses, are stored with an empty root_domain.
+func backfillDomains(ctx context.Context) error {
+	rows, err := pool.Query(ctx, `SELECT id, COALESCE(url, ''), COALESCE(input, ''), COALESCE(host, '') FROM httpx_data WHERE root_domain IS NULL`)
+	if err != nil {
+		return err
+	}
+	type pending struct {
+		id               int64
+		url, input, host string
+	}
+	records, err := pgx.CollectRows(rows, func(row pgx.CollectableRow) (pending, error) {
+		var p pending
+		err := row.Scan(&p.id, &p.url, &p.input, &p.host)
+		return p, err
+	})
+	if err != nil || len(records) == 0 {
+		return err
+	}
+
+	batch := &pgx.Batch{}
+	for _, r := range records {
+		hostname := domain.Hostname(r.url, r.input, r.host)
+		root, sub := domain.Split(hostname)
+		batch.Queue(`UPDATE httpx_data SET hostname = $1, root_domain = $2, subdomain = $3 WHERE id = $4`,
+			hostname, root, sub, r.id)
+	}
+	return pool.SendBatch(ctx, batch).Close()
 }
 
 type ListOptions struct {
-	Query       string
-	URL         string
-	Input       string
-	Title       string
-	A           string
-	Webserver   string
-	Tech        string
-	Host        string
-	Scheme      string
-	Port        string
-	Method      string
-	Path        string
-	Location    string
-	ContentType string
-	StatusCode  int
-	Program     string
-	Platform    string
-	SortBy      string
-	SortOrder   string
-	Limit       int
+	Query            string
+	URL              string
+	Input            string
+	Title            string
+	A                string
+	CNAME            string
+	Webserver        string
+	Tech             string
+	Header           string
+	TechVersion      []technology.Constraint
+	Host             string
+	Scheme           string
+	Port             string
+	Method           string
+	Path             string
+	Location         string
+	RedirectsTo      string
+	ExternalRedirect bool
+	ContentType      string
+	StatusCode       int
+	Program          string
+	Platform         string
+	Source           string
+	RootDomain       string
+	ExcludeWildcards bool
+	Label            string
+	TriageStatus     string
+	Assignee         string
+	Cloud            string
+	ASN              int
+	NotCDN           bool
+	IncludeGone      bool
+	SortBy           string
+	SortOrder        string
+	Limit            int
 }
 
 func List(ctx context.Context, opts ListOptions) ([]models.HTTPXData, error) {
-	query := `SELECT id, port, url, input, location, title, scheme, webserver,
-		content_type, method, host, path, time, a, tech, words, lines,
-		status_code, content_length, program, platform
-		FROM httpx_data WHERE 1=1`
+	where, args := buildFilters(opts)
+	query := `SELECT ` + recordColumns + ` FROM httpx_data WHERE 1=1` + where
+
+	validSortColumns := map[string]bool{
+		"port":           true,
+		"url":            true,
+		"input":          true,
+		"title":          true,
+		"scheme":         true,
+		"webserver":      true,
+		"content_type":   true,
+		"method":         true,
+		"host":           true,
+		"hostname":       true,
+		"path":           true,
+		"location":       true,
+		"a":              true,
+		"tech":           true,
+		"words":          true,
+		"lines":          true,
+		"status_code":    true,
+		"content_length": true,
+		"root_domain":    true,
+		"program":        true,
+		"platform":       true,
+		"created_at":     true,
+	}
+	sortBy := "created_at"
+	if opts.SortBy != "" && validSortColumns[opts.SortBy] {
+		sortBy = opts.SortBy
+	}
+
+	sortOrder := "DESC"
+	if opts.SortOrder == "asc" {
+		sortOrder = "ASC"
+	}
+
+	query += fmt.Sprintf(" ORDER BY %s %s", sortBy, sortOrder)
+
+	if opts.Limit > 0 {
+		query += fmt.Sprintf(" LIMIT %d", opts.Limit)
+	}
+
+	rows, err := pool.Query(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	return pgx.CollectRows(rows, scanRecord)
+}
+
+// recordColumns is the httpx_data column list read by scanRecord.
+const recordColumns = `id, port, url, input, location, title, scheme, webserver,
+	content_type, method, host, path, time, a, cname, tech, words, lines,
+	chain, COALESCE(redirect_url, ''),
+	status_code, content_length, headers, COALESCE(body_hash, ''), COALESCE(favicon, ''), COALESCE(jarm, ''),
+	COALESCE(hostname, ''), COALESCE(root_domain, ''), COALESCE(subdomain, ''),
+	labels, source, program, platform`
+
+func scanRecord(row pgx.CollectableRow) (models.HTTPXData, error) {
+	var d models.HTTPXData
+	err := row.Scan(&d.ID, &d.Port, &d.URL, &d.Input, &d.Location, &d.Title, &d.Scheme,
+		&d.Webserver, &d.ContentType, &d.Method, &d.Host, &d.Path, &d.Time,
+		&d.A, &d.CNAME, &d.Tech, &d.Words, &d.Lines, &d.Chain, &d.RedirectURL, &d.StatusCode, &d.ContentLength,
+		&d.Headers, &d.BodyHash, &d.Favicon, &d.Jarm,
+		&d.Hostname, &d.RootDomain, &d.Subdomain, &d.Labels, &d.Source, &d.Program, &d.Platform)
+	return d, err
+}
+
+// buildFilters turns the filter fields of opts into SQL conditions, each
+// prefixed with " AND", along with their positional arguments.
+func buildFilters(opts ListOptions) (string, []interface{}) {
+	query := ""
 	args := []interface{}{}
 	argNum := 1
 
@@ -128,7 +322,7 @@ func List(ctx context.Context, opts ListOptions) ([]models.HTTPXData, error) {
 		// Single "search" term across common fields.
 		ph := fmt.Sprintf("$%d", argNum)
 		query += fmt.Sprintf(
-			" AND (url ILIKE %[1]s OR input ILIKE %[1]s OR title ILIKE %[1]s OR host ILIKE %[1]s OR webserver ILIKE %[1]s OR content_type ILIKE %[1]s OR tech::text ILIKE %[1]s OR a::text ILIKE %[1]s OR program ILIKE %[1]s OR platform ILIKE %[1]s)",
+			" AND (url ILIKE %[1]s OR input ILIKE %[1]s OR title ILIKE %[1]s OR host ILIKE %[1]s OR hostname ILIKE %[1]s OR webserver ILIKE %[1]s OR content_type ILIKE %[1]s OR tech::text ILIKE %[1]s OR a::text ILIKE %[1]s OR program ILIKE %[1]s OR platform ILIKE %[1]s)",
 			ph,
 		)
 		args = append(args, "%"+opts.Query+"%")
@@ -156,6 +350,11 @@ func List(ctx context.Context, opts ListOptions) ([]models.HTTPXData, error) {
 		args = append(args, "%"+opts.A+"%")
 		argNum++
 	}
+	if opts.CNAME != "" {
+		query += fmt.Sprintf(" AND cname::text ILIKE $%d", argNum)
+		args = append(args, "%"+opts.CNAME+"%")
+		argNum++
+	}
 	if opts.Webserver != "" {
 		query += fmt.Sprintf(" AND webserver ILIKE $%d", argNum)
 		args = append(args, "%"+opts.Webserver+"%")
@@ -166,8 +365,32 @@ func List(ctx context.Context, opts ListOptions) ([]models.HTTPXData, error) {
 		args = append(args, "%"+opts.Tech+"%")
 		argNum++
 	}
+	if opts.Header != "" {
+		// "Name: value" matches the value partially, a bare name matches presence.
+		name, value, hasValue := strings.Cut(opts.Header, ":")
+		if hasValue {
+			query += fmt.Sprintf(" AND headers->>$%d ILIKE $%d", argNum, argNum+1)
+			args = append(args, models.HeaderKey(name), "%"+strings.TrimSpace(value)+"%")
+			argNum += 2
+		} else {
+			query += fmt.Sprintf(" AND headers ? $%d", argNum)
+			args = append(args, models.HeaderKey(name))
+			argNum++
+		}
+	}
+	for _, c := range opts.TechVersion {
+		cond := fmt.Sprintf("t.name ILIKE $%d", argNum)
+		args = append(args, c.Name)
+		argNum++
+		for _, b := range c.Bounds {
+			cond += fmt.Sprintf(" AND t.version_parts %s $%d", b.Op, argNum)
+			args = append(args, b.Version)
+			argNum++
+		}
+		query += " AND EXISTS (SELECT 1 FROM technologies t WHERE t.record_id = httpx_data.id AND " + cond + ")"
+	}
 	if opts.Host != "" {
-		query += fmt.Sprintf(" AND host ILIKE $%d", argNum)
+		query += fmt.Sprintf(" AND (host ILIKE $%[1]d OR hostname ILIKE $%[1]d)", argNum)
 		args = append(args, "%"+opts.Host+"%")
 		argNum++
 	}
@@ -196,6 +419,15 @@ func List(ctx context.Context, opts ListOptions) ([]models.HTTPXData, error) {
 		args = append(args, "%"+opts.Location+"%")
 		argNum++
 	}
+	if opts.RedirectsTo != "" {
+		// Intermediate hops count too.
+		query += fmt.Sprintf(" AND (redirect_url ILIKE $%[1]d OR chain::text ILIKE $%[1]d)", argNum)
+		args = append(args, "%"+opts.RedirectsTo+"%")
+		argNum++
+	}
+	if opts.ExternalRedirect {
+		query += " AND " + externalRedirectSQL
+	}
 	if opts.ContentType != "" {
 		query += fmt.Sprintf(" AND content_type ILIKE $%d", argNum)
 		args = append(args, "%"+opts.ContentType+"%")
@@ -216,59 +448,66 @@ func List(ctx context.Context, opts ListOptions) ([]models.HTTPXData, error) {
 		args = append(args, opts.Platform)
 		argNum++
 	}
-
-	validSortColumns := map[string]bool{
-		"port":           true,
-		"url":            true,
-		"input":          true,
-		"title":          true,
-		"scheme":         true,
-		"webserver":      true,
-		"content_type":   true,
-		"method":         true,
-		"host":           true,
-		"path":           true,
-		"location":       true,
-		"a":              true,
-		"tech":           true,
-		"words":          true,
-		"lines":          true,
-		"status_code":    true,
-		"content_length": true,
-		"program":        true,
-		"platform":       true,
-		"created_at":     true,
+	if opts.Source != "" {
+		query += fmt.Sprintf(" AND source = $%d", argNum)
+		args = append(args, opts.Source)
+		argNum++
 	}
-	sortBy := "created_at"
-	if opts.SortBy != "" && validSortColumns[opts.SortBy] {
-		sortBy = opts.SortBy
+	if opts.RootDomain != "" {
+		query += fmt.Sprintf(" AND root_domain = $%d", argNum)
+		args = append(args, strings.ToLower(opts.RootDomain))
+		argNum++
 	}
-
-	sortOrder := "DESC"
-	if opts.SortOrder == "asc" {
-		sortOrder = "ASC"
+	if opts.ExcludeWildcards {
+		query += " AND NOT wildcard"
 	}
-
-	query += fmt.Sprintf(" ORDER BY %s %s", sortBy, sortOrder)
-
-	if opts.Limit > 0 {
-		query += fmt.Sprintf(" LIMIT %d", opts.Limit)
+	if opts.Label != "" {
+		query += fmt.Sprintf(" AND labels ? $%d", argNum)
+		args = append(args, opts.Label)
+		argNum++
 	}
-
-	rows, err := pool.Query(ctx, query, args...)
-	if err != nil {
-		return nil, err
+	if opts.TriageStatus != "" {
+		// Assets without a status are new.
+		query += fmt.Sprintf(" AND COALESCE(%s, 'new') = $%d", annotationSQL("status"), argNum)
+		args = append(args, opts.TriageStatus)
+		argNum++
+	}
+	if opts.Assignee != "" {
+		query += fmt.Sprintf(" AND %s = $%d", annotationSQL("assignee"), argNum)
+		args = append(args, opts.Assignee)
+		argNum++
+	}
+	if opts.Cloud != "" {
+		query += " AND " + enrichmentSQL(cloudFilter(argNum))
+		args = append(args, opts.Cloud)
+		argNum++
+	}
+	if opts.ASN > 0 {
+		query += " AND " + enrichmentSQL(fmt.Sprintf("e.asn = $%d", argNum))
+		args = append(args, opts.ASN)
+		argNum++
+	}
+	if opts.NotCDN {
+		query += " AND NOT " + enrichmentSQL("e.cdn")
+	}
+	if !opts.IncludeGone {
+		query += " AND NOT " + goneSQL
 	}
-	defer rows.Close()
 
-	results, err := pgx.CollectRows(rows, func(row pgx.CollectableRow) (models.HTTPXData, error) {
-		var d models.HTTPXData
-		err := row.Scan(&d.ID, &d.Port, &d.URL, &d.Input, &d.Location, &d.Title, &d.Scheme,
-			&d.Webserver, &d.ContentType, &d.Method, &d.Host, &d.Path, &d.Time,
-			&d.A, &d.Tech, &d.Words, &d.Lines, &d.StatusCode, &d.ContentLength,
-			&d.Program, &d.Platform)
-		return d, err
-	})
+	return query, args
+}
+
+// Exists reports whether a record with the same URL, or the same hostname
+// when by is "host", is already stored for program.
+func Exists(ctx context.Context, program, by string, data *models.HTTPXData) (bool, error) {
+	query := `SELECT EXISTS (SELECT 1 FROM httpx_data WHERE program = $1 AND url = $2)`
+	value := data.URL
+	if by == "host" {
+		query = `SELECT EXISTS (SELECT 1 FROM httpx_data WHERE program = $1 AND hostname = $2)`
+		value = domain.Hostname(data.URL, data.Input, data.Host)
+	}
 
-	return results, err
+	var exists bool
+	err := pool.QueryRow(ctx, query, program, value).Scan(&exists)
+	return exists, err
 }
@@ -0,0 +1,126 @@
+package db
+
+import (
+	"context"
+	"net"
+	"time"
+
+	"github.com/itsmeashim/rdb/models"
+	"github.com/jackc/pgx/v5"
+)
+
+const dnsSchemaSQL = `
+CREATE INDEX IF NOT EXISTS idx_dns_observations_first_seen ON dns_observations(first_seen);
+
+ALTER TABLE httpx_data ADD COLUMN IF NOT EXISTS dns_recorded BOOLEAN NOT NULL DEFAULT FALSE;
+CREATE INDEX IF NOT EXISTS idx_dns_pending ON httpx_data(id) WHERE NOT dns_recorded;
+`
+
+// dnsTypes are the record types tracked from scans.
+var dnsTypes = []string{"A", "AAAA", "CNAME"}
+
+// recordDNS adds the resolution of a stored record to the DNS history of its
+// host: new values are added and values seen before have last_seen moved
+// forward. Records of IP addresses have no resolution to track.
+func recordDNS(ctx context.Context, tx pgx.Tx, data *models.HTTPXData) error {
+	if data.Hostname == "" || net.ParseIP(data.Hostname) != nil {
+		return nil
+	}
+	batch := &pgx.Batch{}
+	for typ, values := range map[string]models.StringArray{"A": data.A, "AAAA": data.AAAA, "CNAME": data.CNAME} {
+		for _, value := range values {
+			batch.Queue(`
+				INSERT INTO dns_observations (program, host, type, value, source, first_seen, last_seen)
+				VALUES ($1, $2, $3, $4, $5, CURRENT_TIMESTAMP, CURRENT_TIMESTAMP)
+				ON CONFLICT (program, host, type, value, source) DO UPDATE
+				SET last_seen = GREATEST(dns_observations.last_seen, EXCLUDED.last_seen)`,
+				data.Program, data.Hostname, typ, value, data.Source)
+		}
+	}
+	return tx.SendBatch(ctx, batch).Close()
+}
+
+// backfillDNS builds the DNS history of records stored before it was
+// tracked, dating each value by the records that carried it.
+func backfillDNS(ctx context.Context) error {
+	tx, err := pool.Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback(ctx)
+
+	_, err = tx.Exec(ctx, `
+		INSERT INTO dns_observations (program, host, type, value, source, first_seen, last_seen)
+		SELECT COALESCE(h.program, ''), h.hostname, v.type, v.value, h.source, MIN(h.created_at), MAX(h.created_at)
+		FROM httpx_data h
+		CROSS JOIN LATERAL (
+			SELECT 'A'::text, jsonb_array_elements_text(h.a) WHERE jsonb_typeof(h.a) = 'array'
+			UNION ALL
+			SELECT 'CNAME', jsonb_array_elements_text(h.cname) WHERE jsonb_typeof(h.cname) = 'array'
+		) v(type, value)
+		WHERE NOT h.dns_recorded AND h.hostname <> '' AND h.hostname !~ '^[0-9.]+$' AND position(':' IN h.hostname) = 0
+		GROUP BY COALESCE(h.program, ''), h.hostname, v.type, v.value, h.source
+		ON CONFLICT (program, host, type, value, source) DO UPDATE
+		SET first_seen = LEAST(dns_observations.first_seen, EXCLUDED.first_seen),
+		    last_seen = GREATEST(dns_observations.last_seen, EXCLUDED.last_seen)`)
+	if err != nil {
+		return err
+	}
+	if _, err := tx.Exec(ctx, `UPDATE httpx_data SET dns_recorded = TRUE WHERE NOT dns_recorded`); err != nil {
+		return err
+	}
+	return tx.Commit(ctx)
+}
+
+// DNSHistory returns every value a host resolved to, from scans and
+// imported passive DNS, ordered by type and first seen.
+func DNSHistory(ctx context.Context, host, program string) ([]models.DNSObservation, error) {
+	rows, err := pool.Query(ctx, `
+		SELECT program, host, type, value, source, first_seen, last_seen
+		FROM dns_observations
+		WHERE host = LOWER($1) AND ($2 = '' OR program = $2)
+		ORDER BY type, first_seen, value`, host, program)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	return pgx.CollectRows(rows, func(row pgx.CollectableRow) (models.DNSObservation, error) {
+		var o models.DNSObservation
+		err := row.Scan(&o.Program, &o.Host, &o.Type, &o.Value, &o.Source, &o.FirstSeen, &o.LastSeen)
+		return o, err
+	})
+}
+
+// DNSChanges returns the hosts that started resolving to new values within
+// the given duration, one entry per host and record type, with the values
+// seen before and the new ones. Hosts first seen in the window are not
+// changes.
+func DNSChanges(ctx context.Context, within time.Duration, program string) ([]models.DNSChange, error) {
+	rows, err := pool.Query(ctx, `
+		WITH since AS (
+			SELECT CURRENT_TIMESTAMP - $1::interval AS t
+		), o AS (
+			SELECT * FROM dns_observations
+			WHERE type = ANY($3) AND ($2 = '' OR program = $2)
+		)
+		SELECT n.program, n.host, n.type,
+		       array_agg(DISTINCT p.value ORDER BY p.value),
+		       array_agg(DISTINCT n.value ORDER BY n.value),
+		       MIN(n.first_seen)
+		FROM o n
+		JOIN o p ON p.program = n.program AND p.host = n.host AND p.type = n.type
+		WHERE n.first_seen >= (SELECT t FROM since) AND p.first_seen < (SELECT t FROM since)
+		GROUP BY n.program, n.host, n.type
+		ORDER BY MIN(n.first_seen) DESC, n.host`, within, program, dnsTypes)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	return pgx.CollectRows(rows, func(row pgx.CollectableRow) (models.DNSChange, error) {
+		var c models.DNSChange
+		err := row.Scan(&c.Program, &c.Host, &c.Type, &c.Previous, &c.Current, &c.ChangedAt)
+		return c, err
+	})
+}
@@ -0,0 +1,95 @@
+package db
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/itsmeashim/rdb/models"
+	"github.com/jackc/pgx/v5"
+)
+
+const enrichSchemaSQL = `
+CREATE TABLE IF NOT EXISTS ip_enrichment (
+    ip TEXT PRIMARY KEY,
+    cloud TEXT NOT NULL DEFAULT '',
+    service TEXT,
+    region TEXT,
+    cdn BOOLEAN NOT NULL DEFAULT FALSE,
+    asn INT,
+    as_org TEXT,
+    country TEXT,
+    dataset TEXT NOT NULL,
+    enriched_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_ip_enrichment_cloud ON ip_enrichment(cloud);
+CREATE INDEX IF NOT EXISTS idx_ip_enrichment_asn ON ip_enrichment(asn);
+`
+
+// recordIPsSQL selects the A record IPs of the current httpx_data row.
+const recordIPsSQL = `(SELECT jsonb_array_elements_text(httpx_data.a) WHERE jsonb_typeof(httpx_data.a) = 'array')`
+
+// enrichmentSQL matches records with an enriched IP meeting cond, a
+// condition on ip_enrichment e.
+func enrichmentSQL(cond string) string {
+	return `EXISTS (SELECT 1 FROM ip_enrichment e WHERE e.ip IN ` + recordIPsSQL + ` AND ` + cond + `)`
+}
+
+// PendingIPs returns the stored record IPs not yet enriched with the
+// dataset version, or every stored IP when all is set.
+func PendingIPs(ctx context.Context, dataset string, all bool) ([]string, error) {
+	rows, err := pool.Query(ctx, `
+		SELECT DISTINCT v.ip
+		FROM httpx_data
+		CROSS JOIN LATERAL `+recordIPsSQL+` v(ip)
+		LEFT JOIN ip_enrichment e ON e.ip = v.ip
+		WHERE $2 OR e.ip IS NULL OR e.dataset <> $1
+		ORDER BY v.ip`, dataset, all)
+	if err != nil {
+		return nil, err
+	}
+	return pgx.CollectRows(rows, pgx.RowTo[string])
+}
+
+// SaveIPInfo stores the enrichment of IPs made with the dataset version.
+func SaveIPInfo(ctx context.Context, infos []models.IPInfo, dataset string) error {
+	batch := &pgx.Batch{}
+	for _, i := range infos {
+		batch.Queue(`
+			INSERT INTO ip_enrichment (ip, cloud, service, region, cdn, asn, as_org, country, dataset)
+			VALUES ($1, $2, NULLIF($3, ''), NULLIF($4, ''), $5, NULLIF($6, 0), NULLIF($7, ''), NULLIF($8, ''), $9)
+			ON CONFLICT (ip) DO UPDATE SET
+				cloud = EXCLUDED.cloud, service = EXCLUDED.service, region = EXCLUDED.region,
+				cdn = EXCLUDED.cdn, asn = EXCLUDED.asn, as_org = EXCLUDED.as_org,
+				country = EXCLUDED.country, dataset = EXCLUDED.dataset, enriched_at = CURRENT_TIMESTAMP`,
+			i.IP, i.Cloud, i.Service, i.Region, i.CDN, i.ASN, i.ASOrg, i.Country, dataset)
+	}
+	return pool.SendBatch(ctx, batch).Close()
+}
+
+// CloudCounts returns the number of enriched IPs per cloud provider; the
+// empty provider counts self-hosted IPs.
+func CloudCounts(ctx context.Context) (map[string]int64, error) {
+	rows, err := pool.Query(ctx, `SELECT cloud, COUNT(*) FROM ip_enrichment GROUP BY cloud`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	counts := map[string]int64{}
+	for rows.Next() {
+		var cloud string
+		var n int64
+		if err := rows.Scan(&cloud, &n); err != nil {
+			return nil, err
+		}
+		counts[cloud] = n
+	}
+	return counts, rows.Err()
+}
+
+// cloudFilter returns the condition for --cloud; "self-hosted" matches IPs
+// of no known provider.
+func cloudFilter(argNum int) string {
+	return fmt.Sprintf("e.cloud = CASE WHEN LOWER($%[1]d) = 'self-hosted' THEN '' ELSE LOWER($%[1]d) END", argNum)
+}
@@ -0,0 +1,163 @@
+package db
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/itsmeashim/rdb/extract"
+	"github.com/itsmeashim/rdb/models"
+	"github.com/jackc/pgx/v5"
+)
+
+const extractionSchemaSQL = `
+CREATE TABLE IF NOT EXISTS extractions (
+    id SERIAL PRIMARY KEY,
+    record_id INT NOT NULL REFERENCES httpx_data(id) ON DELETE CASCADE,
+    program TEXT NOT NULL,
+    kind TEXT NOT NULL,
+    rule TEXT NOT NULL,
+    value TEXT NOT NULL,
+    status TEXT NOT NULL DEFAULT 'new',
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    UNIQUE (program, kind, value)
+);
+
+CREATE INDEX IF NOT EXISTS idx_extractions_record ON extractions(record_id);
+
+CREATE TABLE IF NOT EXISTS extracted_bodies (
+    kind TEXT NOT NULL,
+    program TEXT NOT NULL,
+    body_hash TEXT NOT NULL,
+    PRIMARY KEY (kind, program, body_hash)
+);
+`
+
+// PendingBodies returns one record per program and body that has not been
+// run through the rules of kind yet.
+func PendingBodies(ctx context.Context, opts ListOptions, kind string) ([]models.BodyRef, error) {
+	cte, args := filteredCTE(opts)
+	query := cte + fmt.Sprintf(`
+		SELECT DISTINCT ON (f.program, f.body_hash) f.id, f.program, f.body_hash
+		FROM f JOIN response_bodies b ON b.hash = f.body_hash
+		WHERE NOT EXISTS (
+			SELECT 1 FROM extracted_bodies e
+			WHERE e.kind = $%d AND e.program = f.program AND e.body_hash = f.body_hash
+		)
+		ORDER BY f.program, f.body_hash, f.id`, len(args)+1)
+	args = append(args, kind)
+
+	rows, err := pool.Query(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	return pgx.CollectRows(rows, func(row pgx.CollectableRow) (models.BodyRef, error) {
+		var b models.BodyRef
+		err := row.Scan(&b.RecordID, &b.Program, &b.Hash)
+		return b, err
+	})
+}
+
+// LoadBody returns the decompressed body stored under hash.
+func LoadBody(ctx context.Context, hash string) ([]byte, error) {
+	var compressed []byte
+	err := pool.QueryRow(ctx, `SELECT body FROM response_bodies WHERE hash = $1`, hash).Scan(&compressed)
+	if err != nil {
+		return nil, err
+	}
+	return decompress(compressed)
+}
+
+// SaveExtractions stores the matches found in a body and marks the body as
+// processed for kind. Values already extracted for the program are skipped.
+// It returns the number of new values.
+func SaveExtractions(ctx context.Context, ref models.BodyRef, kind string, matches []extract.Match) (int64, error) {
+	tx, err := pool.Begin(ctx)
+	if err != nil {
+		return 0, err
+	}
+	defer tx.Rollback(ctx)
+
+	var added int64
+	for _, m := range matches {
+		tag, err := tx.Exec(ctx, `
+			INSERT INTO extractions (record_id, program, kind, rule, value) VALUES ($1, $2, $3, $4, $5)
+			ON CONFLICT (program, kind, value) DO NOTHING`,
+			ref.RecordID, ref.Program, kind, m.Rule, m.Value)
+		if err != nil {
+			return 0, err
+		}
+		added += tag.RowsAffected()
+	}
+
+	_, err = tx.Exec(ctx, `
+		INSERT INTO extracted_bodies (kind, program, body_hash) VALUES ($1, $2, $3)
+		ON CONFLICT DO NOTHING`, kind, ref.Program, ref.Hash)
+	if err != nil {
+		return 0, err
+	}
+
+	return added, tx.Commit(ctx)
+}
+
+type ExtractionOptions struct {
+	Program string
+	Kind    string
+	Rule    string
+	Status  string
+	Value   string
+	Limit   int
+}
+
+func ListExtractions(ctx context.Context, opts ExtractionOptions) ([]models.Extraction, error) {
+	query := `SELECT e.id, e.record_id, h.url, e.program, e.kind, e.rule, e.value, e.status, e.created_at
+		FROM extractions e JOIN httpx_data h ON h.id = e.record_id
+		WHERE 1=1`
+	args := []interface{}{}
+	argNum := 1
+
+	for _, f := range []struct{ column, value string }{
+		{"e.program", opts.Program},
+		{"e.kind", opts.Kind},
+		{"e.rule", opts.Rule},
+		{"e.status", opts.Status},
+	} {
+		if f.value != "" {
+			query += fmt.Sprintf(" AND %s = $%d", f.column, argNum)
+			args = append(args, f.value)
+			argNum++
+		}
+	}
+	if opts.Value != "" {
+		query += fmt.Sprintf(" AND e.value ILIKE $%d", argNum)
+		args = append(args, "%"+opts.Value+"%")
+		argNum++
+	}
+
+	query += " ORDER BY e.program, e.kind, e.rule, e.value"
+	if opts.Limit > 0 {
+		query += fmt.Sprintf(" LIMIT %d", opts.Limit)
+	}
+
+	rows, err := pool.Query(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	return pgx.CollectRows(rows, func(row pgx.CollectableRow) (models.Extraction, error) {
+		var e models.Extraction
+		err := row.Scan(&e.ID, &e.RecordID, &e.URL, &e.Program, &e.Kind, &e.Rule, &e.Value, &e.Status, &e.CreatedAt)
+		return e, err
+	})
+}
+
+// SetExtractionStatus updates the triage status of extractions by ID.
+func SetExtractionStatus(ctx context.Context, ids []int64, status string) (int64, error) {
+	tag, err := pool.Exec(ctx, `UPDATE extractions SET status = $1 WHERE id = ANY($2)`, status, ids)
+	if err != nil {
+		return 0, err
+	}
+	return tag.RowsAffected(), nil
+}
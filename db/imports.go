@@ -0,0 +1,31 @@
+package db
+
+import (
+	"context"
+
+	"github.com/jackc/pgx/v5"
+)
+
+const importSchemaSQL = `
+ALTER TABLE httpx_data ADD COLUMN IF NOT EXISTS source TEXT NOT NULL DEFAULT 'httpx';
+CREATE INDEX IF NOT EXISTS idx_source ON httpx_data(source);
+`
+
+// SourceURLs returns the URLs already stored for program from source, so
+// static imports such as wayback URL lists can skip them.
+func SourceURLs(ctx context.Context, program, source string) (map[string]bool, error) {
+	rows, err := pool.Query(ctx, `SELECT DISTINCT url FROM httpx_data WHERE program = $1 AND source = $2`, program, source)
+	if err != nil {
+		return nil, err
+	}
+	urls, err := pgx.CollectRows(rows, pgx.RowTo[string])
+	if err != nil {
+		return nil, err
+	}
+
+	seen := make(map[string]bool, len(urls))
+	for _, u := range urls {
+		seen[u] = true
+	}
+	return seen, nil
+}
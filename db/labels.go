@@ -0,0 +1,28 @@
+package db
+
+import (
+	"context"
+
+	"github.com/itsmeashim/rdb/models"
+	"github.com/jackc/pgx/v5"
+)
+
+const labelSchemaSQL = `
+ALTER TABLE httpx_data ADD COLUMN IF NOT EXISTS labels JSONB NOT NULL DEFAULT '[]';
+CREATE INDEX IF NOT EXISTS idx_labels ON httpx_data USING GIN (labels);
+`
+
+// SetLabels replaces the labels of the given records.
+func SetLabels(ctx context.Context, labels map[int64]models.StringArray) error {
+	if len(labels) == 0 {
+		return nil
+	}
+	batch := &pgx.Batch{}
+	for id, l := range labels {
+		if l == nil {
+			l = models.StringArray{}
+		}
+		batch.Queue(`UPDATE httpx_data SET labels = $1 WHERE id = $2`, l, id)
+	}
+	return pool.SendBatch(ctx, batch).Close()
+}
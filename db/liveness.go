@@ -0,0 +1,115 @@
+package db
+
+import (
+	"context"
+
+	"github.com/itsmeashim/rdb/models"
+	"github.com/jackc/pgx/v5"
+)
+
+const livenessSchemaSQL = `
+CREATE TABLE IF NOT EXISTS scans (
+    id SERIAL PRIMARY KEY,
+    program TEXT NOT NULL,
+    source TEXT NOT NULL,
+    started_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    finished_at TIMESTAMP,
+    seen INT,
+    missing INT,
+    gone INT
+);
+
+CREATE TABLE IF NOT EXISTS asset_liveness (
+    program TEXT NOT NULL,
+    url TEXT NOT NULL,
+    source TEXT NOT NULL,
+    last_seen TIMESTAMP,
+    missed INT NOT NULL DEFAULT 0,
+    missing_since TIMESTAMP,
+    gone BOOLEAN NOT NULL DEFAULT FALSE,
+    gone_since TIMESTAMP,
+    PRIMARY KEY (program, url, source)
+);
+
+CREATE INDEX IF NOT EXISTS idx_asset_liveness_gone ON asset_liveness(program) WHERE gone;
+`
+
+// goneSQL matches the current httpx_data row when its URL was marked gone.
+const goneSQL = `EXISTS (SELECT 1 FROM asset_liveness l
+	WHERE l.program = httpx_data.program AND l.url = httpx_data.url AND l.source = httpx_data.source AND l.gone)`
+
+// markSeen records that a URL answered: it is live again if it was gone.
+func markSeen(ctx context.Context, tx pgx.Tx, data *models.HTTPXData) error {
+	_, err := tx.Exec(ctx, `
+		INSERT INTO asset_liveness (program, url, source, last_seen)
+		VALUES ($1, $2, $3, CURRENT_TIMESTAMP)
+		ON CONFLICT (program, url, source) DO UPDATE
+		SET last_seen = EXCLUDED.last_seen, missed = 0, missing_since = NULL, gone = FALSE, gone_since = NULL`,
+		data.Program, data.URL, data.Source)
+	return err
+}
+
+// StartScan records the start of a full scan of program by source. Every
+// asset of that source not stored until FinishScan counts as missing.
+func StartScan(ctx context.Context, program, source string) (models.Scan, error) {
+	s := models.Scan{Program: program, Source: source}
+	err := pool.QueryRow(ctx, `
+		INSERT INTO scans (program, source) VALUES ($1, $2)
+		RETURNING id, started_at`, program, source).Scan(&s.ID, &s.StartedAt)
+	return s, err
+}
+
+// FinishScan completes a full scan: assets of the program and source not
+// seen since the scan started have their consecutive miss count raised,
+// and those missing from goneAfter scans in a row are marked gone since
+// the first scan that missed them.
+func FinishScan(ctx context.Context, scan *models.Scan, goneAfter int) error {
+	tx, err := pool.Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback(ctx)
+
+	// Assets stored before liveness was tracked, or outside full scans.
+	_, err = tx.Exec(ctx, `
+		INSERT INTO asset_liveness (program, url, source)
+		SELECT DISTINCT program, url, source FROM httpx_data
+		WHERE program = $1 AND source = $2 AND url IS NOT NULL
+		ON CONFLICT (program, url, source) DO NOTHING`, scan.Program, scan.Source)
+	if err != nil {
+		return err
+	}
+
+	err = tx.QueryRow(ctx, `
+		SELECT COUNT(*) FILTER (WHERE last_seen >= $3), COUNT(*) FILTER (WHERE last_seen IS NULL OR last_seen < $3)
+		FROM asset_liveness WHERE program = $1 AND source = $2`,
+		scan.Program, scan.Source, scan.StartedAt).Scan(&scan.Seen, &scan.Missing)
+	if err != nil {
+		return err
+	}
+
+	_, err = tx.Exec(ctx, `
+		UPDATE asset_liveness SET missed = missed + 1, missing_since = COALESCE(missing_since, $3)
+		WHERE program = $1 AND source = $2 AND (last_seen IS NULL OR last_seen < $3)`,
+		scan.Program, scan.Source, scan.StartedAt)
+	if err != nil {
+		return err
+	}
+
+	tag, err := tx.Exec(ctx, `
+		UPDATE asset_liveness SET gone = TRUE, gone_since = missing_since
+		WHERE program = $1 AND source = $2 AND NOT gone AND missed >= $3`,
+		scan.Program, scan.Source, goneAfter)
+	if err != nil {
+		return err
+	}
+	scan.Gone = tag.RowsAffected()
+
+	_, err = tx.Exec(ctx, `
+		UPDATE scans SET finished_at = CURRENT_TIMESTAMP, seen = $2, missing = $3, gone = $4 WHERE id = $1`,
+		scan.ID, scan.Seen, scan.Missing, scan.Gone)
+	if err != nil {
+		return err
+	}
+	return tx.Commit(ctx)
+}
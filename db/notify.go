@@ -0,0 +1,132 @@
+package db
+
+import (
+	"context"
+	"strconv"
+	"time"
+
+	"github.com/itsmeashim/rdb/domain"
+	"github.com/itsmeashim/rdb/models"
+	"github.com/itsmeashim/rdb/technology"
+	"github.com/jackc/pgx/v5"
+)
+
+const notifySchemaSQL = `
+CREATE TABLE IF NOT EXISTS notification_state (
+    webhook TEXT PRIMARY KEY,
+    last_sent TIMESTAMP NOT NULL
+);
+
+CREATE TABLE IF NOT EXISTS notification_queue (
+    id SERIAL PRIMARY KEY,
+    webhook TEXT NOT NULL,
+    change JSONB NOT NULL,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_notification_queue_webhook ON notification_queue(webhook);
+`
+
+// Changes compares data with what is already stored for its program and
+// returns the new host, new URL, status, title and new tech changes. It
+// must be called before data is inserted.
+func Changes(ctx context.Context, data *models.HTTPXData) ([]models.Change, error) {
+	host := domain.Hostname(data.URL, data.Input, data.Host)
+	change := func(typ, from, to string) models.Change {
+		return models.Change{Type: typ, Program: data.Program, Host: host, URL: data.URL, From: from, To: to}
+	}
+
+	var hostKnown bool
+	err := pool.QueryRow(ctx, `SELECT EXISTS (SELECT 1 FROM httpx_data WHERE program = $1 AND hostname = $2)`,
+		data.Program, host).Scan(&hostKnown)
+	if err != nil {
+		return nil, err
+	}
+	if !hostKnown {
+		return []models.Change{change(models.ChangeNewHost, "", "")}, nil
+	}
+
+	var changes []models.Change
+	var status int
+	var title string
+	err = pool.QueryRow(ctx, `
+		SELECT COALESCE(status_code, 0), COALESCE(title, '') FROM httpx_data
+		WHERE program = $1 AND url = $2
+		ORDER BY id DESC LIMIT 1`, data.Program, data.URL).Scan(&status, &title)
+	switch {
+	case err == pgx.ErrNoRows:
+		changes = append(changes, change(models.ChangeNewURL, "", ""))
+	case err != nil:
+		return nil, err
+	default:
+		if status != data.StatusCode {
+			changes = append(changes, change(models.ChangeStatus, strconv.Itoa(status), strconv.Itoa(data.StatusCode)))
+		}
+		if title != data.Title {
+			changes = append(changes, change(models.ChangeTitle, title, data.Title))
+		}
+	}
+
+	for _, entry := range data.Tech {
+		t := technology.Parse(entry)
+		if t.Name == "" {
+			continue
+		}
+		var known bool
+		err := pool.QueryRow(ctx, `
+			SELECT EXISTS (
+				SELECT 1 FROM technologies t JOIN httpx_data h ON h.id = t.record_id
+				WHERE h.program = $1 AND h.hostname = $2 AND lower(t.name) = lower($3)
+			)`, data.Program, host, t.Name).Scan(&known)
+		if err != nil {
+			return nil, err
+		}
+		if !known {
+			changes = append(changes, change(models.ChangeNewTech, "", entry))
+		}
+	}
+
+	return changes, nil
+}
+
+// LastNotified returns when a digest was last sent to webhook, or the zero
+// time if never.
+func LastNotified(ctx context.Context, webhook string) (time.Time, error) {
+	var last time.Time
+	err := pool.QueryRow(ctx, `SELECT last_sent FROM notification_state WHERE webhook = $1`, webhook).Scan(&last)
+	if err == pgx.ErrNoRows {
+		return time.Time{}, nil
+	}
+	return last, err
+}
+
+func MarkNotified(ctx context.Context, webhook string) error {
+	_, err := pool.Exec(ctx, `
+		INSERT INTO notification_state (webhook, last_sent) VALUES ($1, CURRENT_TIMESTAMP)
+		ON CONFLICT (webhook) DO UPDATE SET last_sent = EXCLUDED.last_sent`, webhook)
+	return err
+}
+
+// QueueNotifications holds back changes for a rate limited webhook until
+// its next digest.
+func QueueNotifications(ctx context.Context, webhook string, changes []models.Change) error {
+	batch := &pgx.Batch{}
+	for _, c := range changes {
+		batch.Queue(`INSERT INTO notification_queue (webhook, change) VALUES ($1, $2)`, webhook, c)
+	}
+	return pool.SendBatch(ctx, batch).Close()
+}
+
+// TakeQueuedNotifications removes and returns the changes queued for webhook.
+func TakeQueuedNotifications(ctx context.Context, webhook string) ([]models.Change, error) {
+	rows, err := pool.Query(ctx, `
+		WITH taken AS (
+			DELETE FROM notification_queue WHERE webhook = $1 RETURNING id, change
+		)
+		SELECT change FROM taken ORDER BY id`, webhook)
+	if err != nil {
+		return nil, err
+	}
+
+	return pgx.CollectRows(rows, pgx.RowTo[models.Change])
+}
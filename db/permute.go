@@ -0,0 +1,56 @@
+package db
+
+import (
+	"context"
+
+	"github.com/itsmeashim/rdb/domain"
+	"github.com/jackc/pgx/v5"
+)
+
+// KnownHosts returns the distinct hostnames, from both the URL and the httpx
+// input, of the records matching opts.
+func KnownHosts(ctx context.Context, opts ListOptions) ([]string, error) {
+	cte, args := filteredCTE(opts)
+	rows, err := pool.Query(ctx, cte+`
+		SELECT f.hostname FROM f WHERE f.hostname <> ''
+		UNION
+		SELECT f.input FROM f WHERE f.input <> ''`, args...)
+	if err != nil {
+		return nil, err
+	}
+	values, err := pgx.CollectRows(rows, pgx.RowTo[string])
+	if err != nil {
+		return nil, err
+	}
+
+	seen := map[string]bool{}
+	hosts := make([]string, 0, len(values))
+	for _, v := range values {
+		h := domain.Hostname(v, v)
+		if h != "" && !seen[h] {
+			seen[h] = true
+			hosts = append(hosts, h)
+		}
+	}
+	return hosts, nil
+}
+
+// HostnamesUnder returns every stored hostname under the given registrable
+// domains, across programs.
+func HostnamesUnder(ctx context.Context, roots []string) (map[string]bool, error) {
+	rows, err := pool.Query(ctx, `
+		SELECT DISTINCT hostname FROM httpx_data WHERE root_domain = ANY($1)`, roots)
+	if err != nil {
+		return nil, err
+	}
+	hosts, err := pgx.CollectRows(rows, pgx.RowTo[string])
+	if err != nil {
+		return nil, err
+	}
+
+	known := make(map[string]bool, len(hosts))
+	for _, h := range hosts {
+		known[h] = true
+	}
+	return known, nil
+}
@@ -0,0 +1,84 @@
+package db
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+
+	"github.com/itsmeashim/rdb/models"
+	"github.com/jackc/pgx/v5"
+)
+
+const pivotSchemaSQL = `
+ALTER TABLE httpx_data ADD COLUMN IF NOT EXISTS favicon TEXT;
+ALTER TABLE httpx_data ADD COLUMN IF NOT EXISTS jarm TEXT;
+CREATE INDEX IF NOT EXISTS idx_favicon ON httpx_data(favicon);
+CREATE INDEX IF NOT EXISTS idx_jarm ON httpx_data(jarm);
+CREATE INDEX IF NOT EXISTS idx_title ON httpx_data(title);
+`
+
+// pivotConditions maps pivot attributes to the condition selecting records
+// sharing a value, given as $1.
+var pivotConditions = map[string]string{
+	"favicon":   "favicon = $1",
+	"jarm":      "jarm = $1",
+	"body-hash": "body_hash = $1",
+	"title":     "title = $1",
+	"ip":        "a @> jsonb_build_array($1::text)",
+}
+
+// PivotAttributes lists the attributes accepted by Pivot.
+var PivotAttributes = []string{"favicon", "jarm", "body-hash", "title", "ip"}
+
+// GetRecord returns a record by numeric ID, or the latest record of a URL.
+func GetRecord(ctx context.Context, idOrURL string) (models.HTTPXData, error) {
+	query := `SELECT ` + recordColumns + ` FROM httpx_data WHERE url = $1 ORDER BY id DESC LIMIT 1`
+	var arg interface{} = idOrURL
+	if id, err := strconv.ParseInt(idOrURL, 10, 64); err == nil {
+		query = `SELECT ` + recordColumns + ` FROM httpx_data WHERE id = $1`
+		arg = id
+	}
+
+	rows, err := pool.Query(ctx, query, arg)
+	if err != nil {
+		return models.HTTPXData{}, err
+	}
+	return pgx.CollectExactlyOneRow(rows, scanRecord)
+}
+
+// Pivot returns the latest record of every other URL sharing value for
+// attr, optionally within one program, and the number of such URLs.
+func Pivot(ctx context.Context, attr, value, program, excludeURL string, limit int) ([]models.HTTPXData, int64, error) {
+	cond, ok := pivotConditions[attr]
+	if !ok {
+		return nil, 0, fmt.Errorf("unknown pivot attribute %q", attr)
+	}
+
+	where := cond + " AND url <> $2"
+	args := []interface{}{value, excludeURL}
+	if program != "" {
+		where += " AND program = $3"
+		args = append(args, program)
+	}
+
+	var total int64
+	err := pool.QueryRow(ctx, `SELECT count(DISTINCT url) FROM httpx_data WHERE `+where, args...).Scan(&total)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	query := `SELECT * FROM (
+		SELECT DISTINCT ON (url) ` + recordColumns + ` FROM httpx_data WHERE ` + where + `
+		ORDER BY url, id DESC
+	) latest ORDER BY program, url`
+	if limit > 0 {
+		query += fmt.Sprintf(" LIMIT %d", limit)
+	}
+
+	rows, err := pool.Query(ctx, query, args...)
+	if err != nil {
+		return nil, 0, err
+	}
+	records, err := pgx.CollectRows(rows, scanRecord)
+	return records, total, err
+}
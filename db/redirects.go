@@ -0,0 +1,151 @@
+package db
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/itsmeashim/rdb/domain"
+	"github.com/itsmeashim/rdb/models"
+	"github.com/jackc/pgx/v5"
+)
+
+const redirectSchemaSQL = `
+ALTER TABLE httpx_data ADD COLUMN IF NOT EXISTS chain JSONB;
+ALTER TABLE httpx_data ADD COLUMN IF NOT EXISTS redirect_url TEXT;
+ALTER TABLE httpx_data ADD COLUMN IF NOT EXISTS redirect_host TEXT;
+ALTER TABLE httpx_data ADD COLUMN IF NOT EXISTS redirect_root TEXT;
+CREATE INDEX IF NOT EXISTS idx_redirect_host ON httpx_data(redirect_host);
+CREATE INDEX IF NOT EXISTS idx_redirect_pending ON httpx_data(id) WHERE redirect_url IS NULL AND location <> '';
+`
+
+// externalRedirectSQL matches records redirecting to another registrable
+// domain, or to another host when either side is an IP address.
+const externalRedirectSQL = `(redirect_url <> '' AND
+	COALESCE(NULLIF(redirect_root, ''), redirect_host) <> COALESCE(NULLIF(root_domain, ''), hostname))`
+
+// schemeRedirectSQL matches records redirecting to their own host on
+// another scheme.
+const schemeRedirectSQL = `(redirect_url <> '' AND redirect_host = hostname AND
+	split_part(redirect_url, '://', 1) <> scheme)`
+
+// redirectTarget returns the final destination of a redirecting response:
+// httpx final_url when redirects were followed, else the last Location of
+// the chain, else the Location header. It returns "" when the response does
+// not redirect.
+func redirectTarget(d *models.HTTPXData) string {
+	if d.FinalURL != "" && d.FinalURL != d.URL {
+		return d.FinalURL
+	}
+	for i := len(d.Chain) - 1; i >= 0; i-- {
+		hop := d.Chain[i]
+		if hop.Location == "" {
+			continue
+		}
+		if hop.RequestURL == "" {
+			hop.RequestURL = d.URL
+		}
+		return domain.Resolve(hop.RequestURL, hop.Location)
+	}
+	return domain.Resolve(d.URL, d.Location)
+}
+
+// redirectColumns returns redirect_url, redirect_host and redirect_root of a
+// record; redirect_url is "" when it does not redirect.
+func redirectColumns(d *models.HTTPXData) (target, host, root string) {
+	target = redirectTarget(d)
+	if target == "" {
+		return "", "", ""
+	}
+	host = domain.Hostname(target)
+	root, _ = domain.Split(host)
+	return target, host, root
+}
+
+// backfillRedirects resolves the redirect destination of records stored
+// before redirects were tracked.
+func backfillRedirects(ctx context.Context) error {
+	rows, err := pool.Query(ctx, `SELECT id, COALESCE(url, ''), location FROM httpx_data WHERE redirect_url IS NULL AND location <> ''`)
+	if err != nil {
+		return err
+	}
+	records, err := pgx.CollectRows(rows, func(row pgx.CollectableRow) (models.HTTPXData, error) {
+		var d models.HTTPXData
+		err := row.Scan(&d.ID, &d.URL, &d.Location)
+		return d, err
+	})
+	if err != nil || len(records) == 0 {
+		return err
+	}
+
+	batch := &pgx.Batch{}
+	for i := range records {
+		target, host, root := redirectColumns(&records[i])
+		batch.Queue(`UPDATE httpx_data SET redirect_url = $1, redirect_host = $2, redirect_root = $3 WHERE id = $4`,
+			target, host, root, records[i].ID)
+	}
+	return pool.SendBatch(ctx, batch).Close()
+}
+
+// ListRedirects groups redirecting records matching opts by destination
+// host, or by full destination URL when byURL is set.
+func ListRedirects(ctx context.Context, opts ListOptions, byURL bool) ([]models.RedirectSummary, error) {
+	cte, args := filteredCTE(opts)
+	dest := "f.redirect_host"
+	if byURL {
+		dest = "f.redirect_url"
+	}
+	query := cte + fmt.Sprintf(`
+		SELECT %[1]s,
+			COALESCE(max(f.redirect_root), ''),
+			bool_or(%[2]s),
+			array_agg(DISTINCT f.hostname ORDER BY f.hostname),
+			array_agg(DISTINCT f.program ORDER BY f.program),
+			count(*)
+		FROM f
+		WHERE f.redirect_url <> ''
+		GROUP BY %[1]s
+		ORDER BY count(DISTINCT f.hostname) DESC, %[1]s`, dest, externalRedirectSQL)
+
+	if opts.Limit > 0 {
+		query += fmt.Sprintf(" LIMIT %d", opts.Limit)
+	}
+
+	rows, err := pool.Query(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	return pgx.CollectRows(rows, func(row pgx.CollectableRow) (models.RedirectSummary, error) {
+		var s models.RedirectSummary
+		err := row.Scan(&s.Destination, &s.RootDomain, &s.External, &s.Hosts, &s.Programs, &s.Records)
+		return s, err
+	})
+}
+
+// ListSchemeRedirects returns the latest record of every URL matching opts
+// that redirects to its own host on another scheme.
+func ListSchemeRedirects(ctx context.Context, opts ListOptions) ([]models.SchemeRedirect, error) {
+	cte, args := filteredCTE(opts)
+	query := cte + `
+		SELECT DISTINCT ON (f.program, f.url) f.hostname, f.url, f.redirect_url, f.program
+		FROM f
+		WHERE ` + schemeRedirectSQL + `
+		ORDER BY f.program, f.url, f.id DESC`
+
+	if opts.Limit > 0 {
+		query += fmt.Sprintf(" LIMIT %d", opts.Limit)
+	}
+
+	rows, err := pool.Query(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	return pgx.CollectRows(rows, func(row pgx.CollectableRow) (models.SchemeRedirect, error) {
+		var s models.SchemeRedirect
+		err := row.Scan(&s.Hostname, &s.URL, &s.Target, &s.Program)
+		return s, err
+	})
+}
@@ -0,0 +1,187 @@
+package db
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/itsmeashim/rdb/models"
+	"github.com/jackc/pgx/v5"
+)
+
+const requestSchemaSQL = `
+CREATE TABLE IF NOT EXISTS http_requests (
+    id SERIAL PRIMARY KEY,
+    record_id INT REFERENCES httpx_data(id) ON DELETE SET NULL,
+    program TEXT NOT NULL,
+    platform TEXT NOT NULL,
+    source TEXT NOT NULL,
+    method TEXT NOT NULL,
+    url TEXT NOT NULL,
+    hostname TEXT NOT NULL,
+    path TEXT NOT NULL,
+    request_headers JSONB,
+    request_body TEXT,
+    status_code INT,
+    response_headers JSONB,
+    content_type TEXT,
+    body_hash TEXT,
+    started_at TIMESTAMP,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_http_requests_entry ON http_requests(program, method, url, COALESCE(started_at, 'epoch'));
+CREATE INDEX IF NOT EXISTS idx_http_requests_host ON http_requests(program, hostname);
+CREATE INDEX IF NOT EXISTS idx_http_requests_unlinked ON http_requests(program) WHERE record_id IS NULL;
+`
+
+// InsertRequest stores a request/response pair, with the response body
+// stored like record bodies but kept even when record body storage is off.
+// It reports false when the same entry (program, method, URL and start time)
+// was imported before; entries without a start time are compared as
+// starting at the epoch, since NULLs never conflict.
+func InsertRequest(ctx context.Context, req *models.HTTPRequest) (bool, error) {
+	tx, err := pool.Begin(ctx)
+	if err != nil {
+		return false, err
+	}
+	defer tx.Rollback(ctx)
+
+	limit := maxBodySize
+	if limit <= 0 {
+		limit = capturedBodySize
+	}
+	if req.BodyHash, err = storeBody(ctx, tx, req.ResponseBody, limit); err != nil {
+		return false, err
+	}
+	var startedAt interface{}
+	if !req.StartedAt.IsZero() {
+		startedAt = req.StartedAt.UTC()
+	}
+
+	err = tx.QueryRow(ctx, `
+		INSERT INTO http_requests (
+			program, platform, source, method, url, hostname, path,
+			request_headers, request_body, status_code, response_headers,
+			content_type, body_hash, started_at
+		) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, NULLIF($9, ''), $10, $11, $12, NULLIF($13, ''), $14)
+		ON CONFLICT DO NOTHING
+		RETURNING id`,
+		req.Program, req.Platform, req.Source, req.Method, req.URL, req.Hostname, req.Path,
+		req.RequestHeaders, req.RequestBody, req.StatusCode, req.ResponseHeaders,
+		req.ContentType, req.BodyHash, startedAt,
+	).Scan(&req.ID)
+	if err == pgx.ErrNoRows {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+	return true, tx.Commit(ctx)
+}
+
+// LinkRequests links the unlinked requests of program to the latest record
+// of their host, preferring a record of the same URL. Requests imported
+// before their host was probed are linked once it is stored.
+func LinkRequests(ctx context.Context, program string) error {
+	_, err := pool.Exec(ctx, `
+		UPDATE http_requests r SET record_id = (
+			SELECT h.id FROM httpx_data h
+			WHERE h.program = r.program AND h.hostname = r.hostname
+			ORDER BY (h.url = r.url) DESC, h.id DESC
+			LIMIT 1
+		)
+		WHERE r.record_id IS NULL AND r.program = $1`, program)
+	return err
+}
+
+// RequestOptions filters stored request/response pairs.
+type RequestOptions struct {
+	Program     string
+	Host        string
+	Path        string
+	Method      string
+	StatusCode  int
+	ContentType string
+	Limit       int
+}
+
+const requestColumns = `
+	id, record_id, program, platform, source, method, url, hostname, path,
+	request_headers, COALESCE(request_body, ''), COALESCE(status_code, 0), response_headers,
+	COALESCE(content_type, ''), COALESCE(body_hash, ''), COALESCE(started_at, created_at)`
+
+func scanRequest(row pgx.CollectableRow) (models.HTTPRequest, error) {
+	var r models.HTTPRequest
+	err := row.Scan(&r.ID, &r.RecordID, &r.Program, &r.Platform, &r.Source, &r.Method, &r.URL, &r.Hostname, &r.Path,
+		&r.RequestHeaders, &r.RequestBody, &r.StatusCode, &r.ResponseHeaders,
+		&r.ContentType, &r.BodyHash, &r.StartedAt)
+	return r, err
+}
+
+// ListRequests returns the stored request/response pairs matching opts,
+// oldest first, without response bodies.
+func ListRequests(ctx context.Context, opts RequestOptions) ([]models.HTTPRequest, error) {
+	query := `SELECT ` + requestColumns + ` FROM http_requests WHERE 1=1`
+	var args []interface{}
+	add := func(cond string, arg interface{}) {
+		args = append(args, arg)
+		query += fmt.Sprintf(" AND "+cond, len(args))
+	}
+
+	if opts.Program != "" {
+		add("program = $%d", opts.Program)
+	}
+	if opts.Host != "" {
+		add("hostname ILIKE $%d", "%"+opts.Host+"%")
+	}
+	if opts.Path != "" {
+		add("path ILIKE $%d", "%"+opts.Path+"%")
+	}
+	if opts.Method != "" {
+		add("method = UPPER($%d)", opts.Method)
+	}
+	if opts.StatusCode > 0 {
+		add("status_code = $%d", opts.StatusCode)
+	}
+	if opts.ContentType != "" {
+		add("content_type ILIKE $%d", "%"+opts.ContentType+"%")
+	}
+
+	query += " ORDER BY COALESCE(started_at, created_at), id"
+	if opts.Limit > 0 {
+		query += fmt.Sprintf(" LIMIT %d", opts.Limit)
+	}
+
+	rows, err := pool.Query(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	return pgx.CollectRows(rows, scanRequest)
+}
+
+// GetRequest returns a stored request/response pair with its response body.
+func GetRequest(ctx context.Context, id int64) (models.HTTPRequest, error) {
+	rows, err := pool.Query(ctx, `SELECT `+requestColumns+` FROM http_requests WHERE id = $1`, id)
+	if err != nil {
+		return models.HTTPRequest{}, err
+	}
+	req, err := pgx.CollectExactlyOneRow(rows, scanRequest)
+	if err == pgx.ErrNoRows {
+		return req, fmt.Errorf("request %d not found", id)
+	}
+	if err != nil || req.BodyHash == "" {
+		return req, err
+	}
+
+	body, err := LoadBody(ctx, req.BodyHash)
+	if err == pgx.ErrNoRows {
+		return req, nil
+	}
+	if err != nil {
+		return req, err
+	}
+	req.ResponseBody = string(body)
+	return req, nil
+}
@@ -0,0 +1,114 @@
+package db
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"io"
+
+	"github.com/itsmeashim/rdb/models"
+	"github.com/jackc/pgx/v5"
+)
+
+const responseSchemaSQL = `
+CREATE TABLE IF NOT EXISTS response_bodies (
+    hash TEXT PRIMARY KEY,
+    body BYTEA NOT NULL,
+    size INT NOT NULL,
+    truncated BOOLEAN NOT NULL DEFAULT FALSE,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+ALTER TABLE httpx_data ADD COLUMN IF NOT EXISTS headers JSONB;
+ALTER TABLE httpx_data ADD COLUMN IF NOT EXISTS body_hash TEXT;
+CREATE INDEX IF NOT EXISTS idx_body_hash ON httpx_data(body_hash);
+`
+
+// maxBodySize is the number of body bytes kept per response; 0 disables
+// body storage. Set from the config in Init.
+var maxBodySize int
+
+// capturedBodySize is the number of body bytes kept per captured request
+// when record body storage is disabled: traffic is imported on purpose, so
+// its bodies are always kept.
+const capturedBodySize = 1 << 20
+
+// storeBody returns the SHA-256 of a response body and, when limit is
+// positive, saves the body gzip-compressed and truncated to limit bytes,
+// deduplicated by that hash. It returns "" for an empty body.
+func storeBody(ctx context.Context, tx pgx.Tx, body string, limit int) (string, error) {
+	if body == "" {
+		return "", nil
+	}
+
+	sum := sha256.Sum256([]byte(body))
+	hash := hex.EncodeToString(sum[:])
+	if limit <= 0 {
+		return hash, nil
+	}
+
+	size := len(body)
+	truncated := size > limit
+	if truncated {
+		body = body[:limit]
+	}
+
+	var buf bytes.Buffer
+	zw := gzip.NewWriter(&buf)
+	if _, err := io.WriteString(zw, body); err != nil {
+		return "", err
+	}
+	if err := zw.Close(); err != nil {
+		return "", err
+	}
+
+	_, err := tx.Exec(ctx, `
+		INSERT INTO response_bodies (hash, body, size, truncated) VALUES ($1, $2, $3, $4)
+		ON CONFLICT (hash) DO NOTHING`, hash, buf.Bytes(), size, truncated)
+	return hash, err
+}
+
+func decompress(data []byte) ([]byte, error) {
+	zr, err := gzip.NewReader(bytes.NewReader(data))
+	if err != nil {
+		return nil, err
+	}
+	defer zr.Close()
+	return io.ReadAll(zr)
+}
+
+// EachResponse calls fn for every record matching opts that has stored
+// headers or a stored body, with the body decompressed.
+func EachResponse(ctx context.Context, opts ListOptions, fn func(models.Response) error) error {
+	cte, args := filteredCTE(opts)
+	query := cte + `
+		SELECT f.id, f.url, f.program, f.headers, b.body
+		FROM f LEFT JOIN response_bodies b ON b.hash = f.body_hash
+		WHERE f.headers IS NOT NULL OR b.body IS NOT NULL
+		ORDER BY f.id`
+
+	rows, err := pool.Query(ctx, query, args...)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var r models.Response
+		var compressed []byte
+		if err := rows.Scan(&r.ID, &r.URL, &r.Program, &r.Headers, &compressed); err != nil {
+			return err
+		}
+		if compressed != nil {
+			if r.Body, err = decompress(compressed); err != nil {
+				return err
+			}
+		}
+		if err := fn(r); err != nil {
+			return err
+		}
+	}
+	return rows.Err()
+}
@@ -0,0 +1,188 @@
+package db
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/itsmeashim/rdb/domain"
+	"github.com/itsmeashim/rdb/models"
+	"github.com/jackc/pgx/v5"
+)
+
+const subdomainSchemaSQL = `
+CREATE TABLE IF NOT EXISTS subdomains (
+    program TEXT NOT NULL,
+    hostname TEXT NOT NULL,
+    root_domain TEXT,
+    source TEXT NOT NULL,
+    first_seen TIMESTAMP NOT NULL,
+    last_seen TIMESTAMP NOT NULL,
+    PRIMARY KEY (program, hostname, source)
+);
+
+CREATE INDEX IF NOT EXISTS idx_subdomains_hostname ON subdomains(hostname);
+CREATE INDEX IF NOT EXISTS idx_subdomains_root_domain ON subdomains(root_domain);
+
+CREATE TABLE IF NOT EXISTS dns_observations (
+    id SERIAL PRIMARY KEY,
+    program TEXT NOT NULL,
+    host TEXT NOT NULL,
+    type TEXT NOT NULL,
+    value TEXT NOT NULL,
+    source TEXT NOT NULL,
+    first_seen TIMESTAMP NOT NULL,
+    last_seen TIMESTAMP NOT NULL,
+    UNIQUE (program, host, type, value, source)
+);
+
+CREATE INDEX IF NOT EXISTS idx_dns_observations_host ON dns_observations(host);
+`
+
+// SaveObservations records the names and DNS records observed for program,
+// widening the first and last seen dates of those already known.
+// Observations without dates are dated now. It returns the number of names
+// not known for the program before.
+func SaveObservations(ctx context.Context, program string, obs []models.DNSObservation) (int64, error) {
+	tx, err := pool.Begin(ctx)
+	if err != nil {
+		return 0, err
+	}
+	defer tx.Rollback(ctx)
+
+	// Sightings of one name are merged first; crt.sh lists a name once per
+	// certificate.
+	type nameKey struct{ host, source string }
+	names := map[nameKey]*models.DNSObservation{}
+	var order []nameKey
+	now := time.Now().UTC()
+
+	batch := &pgx.Batch{}
+	for i := range obs {
+		o := obs[i]
+		if o.FirstSeen.IsZero() {
+			o.FirstSeen = now
+		}
+		if o.LastSeen.IsZero() || o.LastSeen.Before(o.FirstSeen) {
+			o.LastSeen = o.FirstSeen
+		}
+
+		k := nameKey{o.Host, o.Source}
+		if n, ok := names[k]; ok {
+			if o.FirstSeen.Before(n.FirstSeen) {
+				n.FirstSeen = o.FirstSeen
+			}
+			if o.LastSeen.After(n.LastSeen) {
+				n.LastSeen = o.LastSeen
+			}
+		} else {
+			names[k] = &o
+			order = append(order, k)
+		}
+
+		if o.Type != "" && o.Value != "" {
+			batch.Queue(`
+				INSERT INTO dns_observations (program, host, type, value, source, first_seen, last_seen)
+				VALUES ($1, $2, $3, $4, $5, $6, $7)
+				ON CONFLICT (program, host, type, value, source) DO UPDATE
+				SET first_seen = LEAST(dns_observations.first_seen, EXCLUDED.first_seen),
+				    last_seen = GREATEST(dns_observations.last_seen, EXCLUDED.last_seen)`,
+				program, o.Host, o.Type, o.Value, o.Source, o.FirstSeen, o.LastSeen)
+		}
+	}
+	if err := tx.SendBatch(ctx, batch).Close(); err != nil {
+		return 0, err
+	}
+
+	batch = &pgx.Batch{}
+	for _, k := range order {
+		n := names[k]
+		root, _ := domain.Split(n.Host)
+		batch.Queue(`
+			WITH known AS (
+				SELECT 1 FROM subdomains WHERE program = $1 AND hostname = $2
+			), upsert AS (
+				INSERT INTO subdomains (program, hostname, root_domain, source, first_seen, last_seen)
+				VALUES ($1, $2, NULLIF($3, ''), $4, $5, $6)
+				ON CONFLICT (program, hostname, source) DO UPDATE
+				SET first_seen = LEAST(subdomains.first_seen, EXCLUDED.first_seen),
+				    last_seen = GREATEST(subdomains.last_seen, EXCLUDED.last_seen)
+			)
+			SELECT NOT EXISTS (SELECT 1 FROM known)`,
+			program, n.Host, root, n.Source, n.FirstSeen, n.LastSeen)
+	}
+	results := tx.SendBatch(ctx, batch)
+	var added int64
+	for range order {
+		var isNew bool
+		if err := results.QueryRow().Scan(&isNew); err != nil {
+			results.Close()
+			return 0, err
+		}
+		if isNew {
+			added++
+		}
+	}
+	if err := results.Close(); err != nil {
+		return 0, err
+	}
+	return added, tx.Commit(ctx)
+}
+
+// SubdomainOptions filters the known subdomains.
+type SubdomainOptions struct {
+	Program    string
+	RootDomain string
+	Source     string
+	NotProbed  bool
+	Limit      int
+}
+
+// KnownSubdomains returns the names known from imported passive data, one
+// per program and hostname with the sources it was seen in. A name is
+// probed when the program has an httpx record of that hostname.
+func KnownSubdomains(ctx context.Context, opts SubdomainOptions) ([]models.Subdomain, error) {
+	query := `
+		SELECT s.program, s.hostname, COALESCE(s.root_domain, ''),
+		       array_agg(DISTINCT s.source ORDER BY s.source), MIN(s.first_seen), MAX(s.last_seen),
+		       EXISTS (SELECT 1 FROM httpx_data h
+		           WHERE h.hostname = s.hostname AND h.program = s.program AND h.source = 'httpx')
+		FROM subdomains s WHERE 1=1`
+	var args []interface{}
+	add := func(cond string, arg interface{}) {
+		args = append(args, arg)
+		query += fmt.Sprintf(" AND "+cond, len(args))
+	}
+
+	if opts.Program != "" {
+		add("s.program = $%d", opts.Program)
+	}
+	if opts.RootDomain != "" {
+		add("s.root_domain = LOWER($%d)", opts.RootDomain)
+	}
+	if opts.NotProbed {
+		query += " AND NOT EXISTS (SELECT 1 FROM httpx_data h WHERE h.hostname = s.hostname AND h.program = s.program AND h.source = 'httpx')"
+	}
+	query += " GROUP BY s.program, s.hostname, s.root_domain"
+	if opts.Source != "" {
+		args = append(args, opts.Source)
+		query += fmt.Sprintf(" HAVING bool_or(s.source = $%d)", len(args))
+	}
+
+	query += " ORDER BY s.program, s.hostname"
+	if opts.Limit > 0 {
+		query += fmt.Sprintf(" LIMIT %d", opts.Limit)
+	}
+
+	rows, err := pool.Query(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	return pgx.CollectRows(rows, func(row pgx.CollectableRow) (models.Subdomain, error) {
+		var s models.Subdomain
+		err := row.Scan(&s.Program, &s.Hostname, &s.RootDomain, &s.Sources, &s.FirstSeen, &s.LastSeen, &s.Probed)
+		return s, err
+	})
+}
@@ -0,0 +1,160 @@
+package db
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/itsmeashim/rdb/models"
+	"github.com/jackc/pgx/v5"
+)
+
+const takeoverSchemaSQL = `
+ALTER TABLE httpx_data ADD COLUMN IF NOT EXISTS cname JSONB;
+
+CREATE TABLE IF NOT EXISTS takeover_candidates (
+    id SERIAL PRIMARY KEY,
+    program TEXT NOT NULL,
+    hostname TEXT NOT NULL,
+    url TEXT NOT NULL,
+    service TEXT NOT NULL,
+    cname TEXT NOT NULL,
+    evidence TEXT NOT NULL,
+    confidence TEXT NOT NULL,
+    gone BOOLEAN NOT NULL DEFAULT FALSE,
+    first_seen TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    last_seen TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    UNIQUE (program, hostname, service)
+);
+`
+
+// Takeover candidate states reported by SaveTakeoverRun.
+const (
+	TakeoverNew  = "new"
+	TakeoverSeen = "seen"
+	TakeoverGone = "gone"
+)
+
+// LatestRecords returns the latest record of every URL matching opts.
+func LatestRecords(ctx context.Context, opts ListOptions) ([]models.HTTPXData, error) {
+	cte, args := filteredCTE(opts)
+	query := cte + `
+		SELECT ` + recordColumns + ` FROM (
+			SELECT DISTINCT ON (program, url) * FROM f ORDER BY program, url, id DESC
+		) latest ORDER BY program, hostname, url`
+
+	rows, err := pool.Query(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	return pgx.CollectRows(rows, scanRecord)
+}
+
+// SaveTakeoverRun records the candidates found when checking the given
+// hosts (program and hostname pairs). Candidates are returned with their
+// state, new or seen before, followed by the previously recorded candidates
+// of those hosts that were not found again, with state gone.
+func SaveTakeoverRun(ctx context.Context, checked []models.Target, found []models.TakeoverCandidate) ([]models.TakeoverCandidate, error) {
+	tx, err := pool.Begin(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback(ctx)
+
+	results := make([]models.TakeoverCandidate, 0, len(found))
+	for _, c := range found {
+		// A candidate that was gone and shows up again is reported as new.
+		var wasActive bool
+		err := tx.QueryRow(ctx, `
+			SELECT NOT gone FROM takeover_candidates WHERE program = $1 AND hostname = $2 AND service = $3`,
+			c.Program, c.Hostname, c.Service).Scan(&wasActive)
+		if err != nil && err != pgx.ErrNoRows {
+			return nil, err
+		}
+
+		err = tx.QueryRow(ctx, `
+			INSERT INTO takeover_candidates (program, hostname, url, service, cname, evidence, confidence)
+			VALUES ($1, $2, $3, $4, $5, $6, $7)
+			ON CONFLICT (program, hostname, service) DO UPDATE
+			SET url = EXCLUDED.url, cname = EXCLUDED.cname, evidence = EXCLUDED.evidence,
+				confidence = EXCLUDED.confidence, gone = FALSE, last_seen = CURRENT_TIMESTAMP
+			RETURNING id, first_seen, last_seen`,
+			c.Program, c.Hostname, c.URL, c.Service, c.CNAME, c.Evidence, c.Confidence,
+		).Scan(&c.ID, &c.FirstSeen, &c.LastSeen)
+		if err != nil {
+			return nil, err
+		}
+
+		c.State = TakeoverNew
+		if wasActive {
+			c.State = TakeoverSeen
+		}
+		results = append(results, c)
+	}
+
+	programs := make([]string, len(checked))
+	hosts := make([]string, len(checked))
+	for i, t := range checked {
+		programs[i], hosts[i] = t.Program, t.Value
+	}
+	// CURRENT_TIMESTAMP is fixed for the transaction, so every candidate
+	// saved above has last_seen equal to it.
+	rows, err := tx.Query(ctx, `
+		UPDATE takeover_candidates SET gone = TRUE
+		WHERE NOT gone AND last_seen < CURRENT_TIMESTAMP
+			AND (program, hostname) IN (SELECT * FROM unnest($1::text[], $2::text[]))
+		RETURNING id, program, hostname, url, service, cname, evidence, confidence, first_seen, last_seen`,
+		programs, hosts)
+	if err != nil {
+		return nil, err
+	}
+	gone, err := pgx.CollectRows(rows, scanTakeoverCandidate)
+	if err != nil {
+		return nil, err
+	}
+	for _, c := range gone {
+		c.State = TakeoverGone
+		results = append(results, c)
+	}
+
+	return results, tx.Commit(ctx)
+}
+
+// ListTakeoverCandidates returns the recorded candidates, newest first,
+// including those no longer found when includeGone is set.
+func ListTakeoverCandidates(ctx context.Context, program, service string, includeGone bool, limit int) ([]models.TakeoverCandidate, error) {
+	query := `
+		SELECT id, program, hostname, url, service, cname, evidence, confidence, first_seen, last_seen, gone
+		FROM takeover_candidates
+		WHERE ($1 = '' OR program = $1) AND ($2 = '' OR service ILIKE '%' || $2 || '%') AND ($3 OR NOT gone)
+		ORDER BY first_seen DESC, id DESC`
+	if limit > 0 {
+		query += fmt.Sprintf(" LIMIT %d", limit)
+	}
+
+	rows, err := pool.Query(ctx, query, program, service, includeGone)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	return pgx.CollectRows(rows, func(row pgx.CollectableRow) (models.TakeoverCandidate, error) {
+		var c models.TakeoverCandidate
+		var gone bool
+		err := row.Scan(&c.ID, &c.Program, &c.Hostname, &c.URL, &c.Service, &c.CNAME,
+			&c.Evidence, &c.Confidence, &c.FirstSeen, &c.LastSeen, &gone)
+		c.State = TakeoverSeen
+		if gone {
+			c.State = TakeoverGone
+		}
+		return c, err
+	})
+}
+
+func scanTakeoverCandidate(row pgx.CollectableRow) (models.TakeoverCandidate, error) {
+	var c models.TakeoverCandidate
+	err := row.Scan(&c.ID, &c.Program, &c.Hostname, &c.URL, &c.Service, &c.CNAME,
+		&c.Evidence, &c.Confidence, &c.FirstSeen, &c.LastSeen)
+	return c, err
+}
@@ -0,0 +1,124 @@
+package db
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/itsmeashim/rdb/models"
+	"github.com/itsmeashim/rdb/technology"
+	"github.com/jackc/pgx/v5"
+)
+
+const techSchemaSQL = `
+CREATE TABLE IF NOT EXISTS technologies (
+    id SERIAL PRIMARY KEY,
+    record_id INT NOT NULL REFERENCES httpx_data(id) ON DELETE CASCADE,
+    name TEXT NOT NULL,
+    version TEXT,
+    version_parts INT[],
+    category TEXT
+);
+
+CREATE INDEX IF NOT EXISTS idx_technologies_record ON technologies(record_id);
+CREATE INDEX IF NOT EXISTS idx_technologies_name ON technologies(lower(name));
+
+ALTER TABLE httpx_data ADD COLUMN IF NOT EXISTS tech_parsed BOOLEAN NOT NULL DEFAULT FALSE;
+CREATE INDEX IF NOT EXISTS idx_tech_pending ON httpx_data(id) WHERE NOT tech_parsed;
+`
+
+// insertTechnologies stores the parsed tech entries of a record.
+func insertTechnologies(ctx context.Context, tx pgx.Tx, recordID int64, tech models.StringArray) error {
+	for _, entry := range tech {
+		t := technology.Parse(entry)
+		if t.Name == "" {
+			continue
+		}
+		_, err := tx.Exec(ctx, `
+			INSERT INTO technologies (record_id, name, version, version_parts, category)
+			VALUES ($1, $2, $3, $4, $5)
+		`, recordID, t.Name, t.Version, technology.VersionParts(t.Version), t.Category)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// backfillTechnologies parses the tech of records stored before the
+// technologies table existed.
+func backfillTechnologies(ctx context.Context) error {
+	rows, err := pool.Query(ctx, `SELECT id, tech FROM httpx_data WHERE NOT tech_parsed`)
+	if err != nil {
+		return err
+	}
+	type pending struct {
+		id   int64
+		tech models.StringArray
+	}
+	records, err := pgx.CollectRows(rows, func(row pgx.CollectableRow) (pending, error) {
+		var p pending
+		err := row.Scan(&p.id, &p.tech)
+		return p, err
+	})
+	if err != nil || len(records) == 0 {
+		return err
+	}
+
+	tx, err := pool.Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback(ctx)
+
+	for _, r := range records {
+		if err := insertTechnologies(ctx, tx, r.id, r.tech); err != nil {
+			return err
+		}
+		if _, err := tx.Exec(ctx, `UPDATE httpx_data SET tech_parsed = TRUE WHERE id = $1`, r.id); err != nil {
+			return err
+		}
+	}
+	return tx.Commit(ctx)
+}
+
+// ListTechnologies returns one row per technology and program with the
+// versions seen and the number of distinct hosts running each.
+func ListTechnologies(ctx context.Context, opts ListOptions, name string) ([]models.TechSummary, error) {
+	cte, args := filteredCTE(opts)
+	query := cte + `,
+		t AS (
+			SELECT t.name, t.category, COALESCE(t.version, '') AS version, t.version_parts, f.hostname AS host, f.program
+			FROM technologies t JOIN f ON f.id = t.record_id`
+	if name != "" {
+		query += fmt.Sprintf(" WHERE t.name ILIKE $%d", len(args)+1)
+		args = append(args, "%"+name+"%")
+	}
+	query += `
+		),
+		v AS (
+			SELECT name, program, version, version_parts, count(DISTINCT host) AS hosts
+			FROM t GROUP BY name, program, version, version_parts
+		)
+		SELECT t.name, COALESCE(max(t.category), ''), t.program, count(DISTINCT t.host) AS hosts,
+			(SELECT jsonb_agg(jsonb_build_object('version', v.version, 'hosts', v.hosts) ORDER BY v.version_parts NULLS FIRST, v.version)
+				FROM v WHERE v.name = t.name AND v.program = t.program)
+		FROM t
+		GROUP BY t.name, t.program
+		ORDER BY hosts DESC, t.name, t.program`
+
+	if opts.Limit > 0 {
+		query += fmt.Sprintf(" LIMIT %d", opts.Limit)
+	}
+
+	rows, err := pool.Query(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	return pgx.CollectRows(rows, func(row pgx.CollectableRow) (models.TechSummary, error) {
+		var s models.TechSummary
+		err := row.Scan(&s.Name, &s.Category, &s.Program, &s.Hosts, &s.Versions)
+		return s, err
+	})
+}
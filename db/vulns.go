@@ -0,0 +1,100 @@
+package db
+
+import (
+	"context"
+
+	"github.com/itsmeashim/rdb/models"
+	"github.com/itsmeashim/rdb/vuln"
+	"github.com/jackc/pgx/v5"
+)
+
+const vulnSchemaSQL = `
+CREATE TABLE IF NOT EXISTS vulnerabilities (
+    id SERIAL PRIMARY KEY,
+    cve TEXT NOT NULL,
+    vendor TEXT,
+    product TEXT NOT NULL,
+    exact_version INT[],
+    version_start INT[],
+    start_inclusive BOOLEAN,
+    version_end INT[],
+    end_inclusive BOOLEAN,
+    cvss NUMERIC(3, 1),
+    source TEXT
+);
+
+CREATE INDEX IF NOT EXISTS idx_vulnerabilities_product ON vulnerabilities(product);
+CREATE INDEX IF NOT EXISTS idx_vulnerabilities_source ON vulnerabilities(source);
+`
+
+// ReplaceVulnerabilities replaces every range previously loaded from source
+// with ranges, so re-syncing a refreshed feed file does not duplicate rows.
+func ReplaceVulnerabilities(ctx context.Context, source string, ranges []vuln.Range) (int64, error) {
+	tx, err := pool.Begin(ctx)
+	if err != nil {
+		return 0, err
+	}
+	defer tx.Rollback(ctx)
+
+	if _, err := tx.Exec(ctx, `DELETE FROM vulnerabilities WHERE source = $1`, source); err != nil {
+		return 0, err
+	}
+
+	n, err := tx.CopyFrom(ctx, pgx.Identifier{"vulnerabilities"},
+		[]string{"cve", "vendor", "product", "exact_version", "version_start", "start_inclusive",
+			"version_end", "end_inclusive", "cvss", "source"},
+		pgx.CopyFromSlice(len(ranges), func(i int) ([]any, error) {
+			r := ranges[i]
+			return []any{r.CVE, r.Vendor, r.Product, r.Exact, r.Start, r.StartIncl,
+				r.End, r.EndIncl, r.CVSS, r.Source}, nil
+		}))
+	if err != nil {
+		return 0, err
+	}
+
+	return n, tx.Commit(ctx)
+}
+
+// ListVulnRanges returns the loaded ranges affecting any of products.
+func ListVulnRanges(ctx context.Context, products []string) ([]vuln.Range, error) {
+	rows, err := pool.Query(ctx, `
+		SELECT cve, COALESCE(vendor, ''), product, exact_version, version_start,
+			COALESCE(start_inclusive, FALSE), version_end, COALESCE(end_inclusive, FALSE),
+			COALESCE(cvss, 0)::float8, COALESCE(source, '')
+		FROM vulnerabilities
+		WHERE product = ANY($1)`, products)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	return pgx.CollectRows(rows, func(row pgx.CollectableRow) (vuln.Range, error) {
+		var r vuln.Range
+		err := row.Scan(&r.CVE, &r.Vendor, &r.Product, &r.Exact, &r.Start, &r.StartIncl,
+			&r.End, &r.EndIncl, &r.CVSS, &r.Source)
+		return r, err
+	})
+}
+
+// ListTechInstances returns the distinct host, technology and version
+// combinations of the records matching opts that have a parsed version.
+func ListTechInstances(ctx context.Context, opts ListOptions) ([]models.TechInstance, error) {
+	cte, args := filteredCTE(opts)
+	query := cte + `
+		SELECT DISTINCT f.hostname, t.name, t.version, t.version_parts, f.program
+		FROM technologies t JOIN f ON f.id = t.record_id
+		WHERE t.version_parts IS NOT NULL
+		ORDER BY f.hostname, t.name`
+
+	rows, err := pool.Query(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	return pgx.CollectRows(rows, func(row pgx.CollectableRow) (models.TechInstance, error) {
+		var t models.TechInstance
+		err := row.Scan(&t.Host, &t.Name, &t.Version, &t.VersionParts, &t.Program)
+		return t, err
+	})
+}
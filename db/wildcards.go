@@ -0,0 +1,103 @@
+package db
+
+import (
+	"context"
+
+	"github.com/itsmeashim/rdb/models"
+	"github.com/jackc/pgx/v5"
+)
+
+const wildcardSchemaSQL = `
+CREATE TABLE IF NOT EXISTS wildcards (
+    id SERIAL PRIMARY KEY,
+    program TEXT NOT NULL,
+    parent TEXT NOT NULL,
+    ips TEXT NOT NULL,
+    status_code INT,
+    host_count INT NOT NULL,
+    detected_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_wildcards_program ON wildcards(program);
+
+ALTER TABLE httpx_data ADD COLUMN IF NOT EXISTS wildcard BOOLEAN NOT NULL DEFAULT FALSE;
+`
+
+// wildcardGroupsSQL selects the sibling subdomains of a parent domain that
+// share the same IP set, status code and (bucketed) content length.
+// $1 is the program (empty for all), $2 the length tolerance and $3 the minimum
+// number of hosts.
+const wildcardGroupsSQL = `
+	WITH s AS (
+		SELECT id, program, hostname,
+			substr(hostname, strpos(hostname, '.') + 1) AS parent,
+			COALESCE((SELECT string_agg(ip, ',' ORDER BY ip) FROM jsonb_array_elements_text(a) ip), '') AS ips,
+			status_code,
+			CASE WHEN $2::float8 > 0 AND content_length > 0
+				THEN floor(ln(content_length) / ln(1 + $2::float8))::int
+				ELSE content_length END AS len
+		FROM httpx_data
+		WHERE root_domain <> '' AND hostname <> root_domain AND ($1 = '' OR program = $1)
+	),
+	g AS (
+		SELECT program, parent, ips, status_code, len, count(DISTINCT hostname) AS hosts
+		FROM s
+		WHERE ips <> ''
+		GROUP BY program, parent, ips, status_code, len
+		HAVING count(DISTINCT hostname) >= $3
+	)`
+
+// DetectWildcards flags parent domains whose subdomains share the same IPs,
+// status code and content length on at least minHosts hosts, and marks their
+// records as wildcard. Previous results for the program are replaced. It
+// returns the number of wildcard groups found.
+func DetectWildcards(ctx context.Context, program string, tolerance float64, minHosts int) (int64, error) {
+	tx, err := pool.Begin(ctx)
+	if err != nil {
+		return 0, err
+	}
+	defer tx.Rollback(ctx)
+
+	if _, err := tx.Exec(ctx, `DELETE FROM wildcards WHERE $1 = '' OR program = $1`, program); err != nil {
+		return 0, err
+	}
+	if _, err := tx.Exec(ctx, `UPDATE httpx_data SET wildcard = FALSE WHERE wildcard AND ($1 = '' OR program = $1)`, program); err != nil {
+		return 0, err
+	}
+
+	tag, err := tx.Exec(ctx, wildcardGroupsSQL+`
+		INSERT INTO wildcards (program, parent, ips, status_code, host_count)
+		SELECT DISTINCT ON (program, parent, ips, status_code) program, parent, ips, status_code, hosts
+		FROM g ORDER BY program, parent, ips, status_code, hosts DESC`, program, tolerance, minHosts)
+	if err != nil {
+		return 0, err
+	}
+
+	_, err = tx.Exec(ctx, wildcardGroupsSQL+`
+		UPDATE httpx_data h SET wildcard = TRUE
+		FROM s JOIN g USING (program, parent, ips, status_code, len)
+		WHERE h.id = s.id`, program, tolerance, minHosts)
+	if err != nil {
+		return 0, err
+	}
+
+	return tag.RowsAffected(), tx.Commit(ctx)
+}
+
+func ListWildcards(ctx context.Context, program string) ([]models.Wildcard, error) {
+	rows, err := pool.Query(ctx, `
+		SELECT program, parent, ips, COALESCE(status_code, 0), host_count, detected_at
+		FROM wildcards
+		WHERE $1 = '' OR program = $1
+		ORDER BY host_count DESC, parent`, program)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	return pgx.CollectRows(rows, func(row pgx.CollectableRow) (models.Wildcard, error) {
+		var w models.Wildcard
+		err := row.Scan(&w.Program, &w.Parent, &w.IPs, &w.StatusCode, &w.Hosts, &w.DetectedAt)
+		return w, err
+	})
+}
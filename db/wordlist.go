@@ -0,0 +1,33 @@
+package db
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/jackc/pgx/v5"
+)
+
+// wordlistSources selects the distinct source values each wordlist kind is
+// mined from.
+var wordlistSources = map[string]string{
+	"subdomains":  `SELECT DISTINCT f.subdomain FROM f WHERE f.subdomain <> ''`,
+	"paths":       `SELECT f.url FROM f WHERE f.url <> '' UNION SELECT f.redirect_url FROM f WHERE f.redirect_url <> ''`,
+	"params":      `SELECT f.url FROM f WHERE f.url LIKE '%?%' UNION SELECT f.redirect_url FROM f WHERE f.redirect_url LIKE '%?%'`,
+	"title-words": `SELECT DISTINCT f.title FROM f WHERE f.title <> ''`,
+}
+
+// WordlistSources returns the distinct values of the records matching opts
+// that words of kind are mined from.
+func WordlistSources(ctx context.Context, opts ListOptions, kind string) ([]string, error) {
+	source, ok := wordlistSources[kind]
+	if !ok {
+		return nil, fmt.Errorf("unknown wordlist kind %q", kind)
+	}
+	cte, args := filteredCTE(opts)
+
+	rows, err := pool.Query(ctx, cte+source, args...)
+	if err != nil {
+		return nil, err
+	}
+	return pgx.CollectRows(rows, pgx.RowTo[string])
+}
@@ -0,0 +1,65 @@
+package domain
+
+import (
+	"net"
+	"net/url"
+	"strings"
+
+	"golang.org/x/net/publicsuffix"
+)
+
+// Hostname extracts the lowercased hostname from a URL, falling back to the
+// given alternatives (e.g. httpx input) when the URL cannot be parsed.
+func Hostname(rawURL string, fallbacks ...string) string {
+	if u, err := url.Parse(rawURL); err == nil && u.Hostname() != "" {
+		return strings.ToLower(u.Hostname())
+	}
+	for _, f := range fallbacks {
+		if f == "" {
+			continue
+		}
+		if u, err := url.Parse("//" + f); err == nil && u.Hostname() != "" {
+			return strings.ToLower(u.Hostname())
+		}
+	}
+	return ""
+}
+
+// Split returns the registrable domain (eTLD+1) of host according to the
+// Public Suffix List embedded in the binary, and the subdomain labels in
+// front of it. Both are empty for IP addresses and bare public suffixes.
+func Split(host string) (root, sub string) {
+	host = strings.TrimSuffix(strings.ToLower(host), ".")
+	if host == "" || net.ParseIP(host) != nil {
+		return "", ""
+	}
+
+	root, err := publicsuffix.EffectiveTLDPlusOne(host)
+	if err != nil {
+		return "", ""
+	}
+	sub = strings.TrimSuffix(strings.TrimSuffix(host, root), ".")
+	return root, sub
+}
+
+// Resolve returns ref, e.g. a Location header, resolved against base as an
+// absolute URL, or "" when either cannot be parsed.
+func Resolve(base, ref string) string {
+	ref = strings.TrimSpace(ref)
+	if ref == "" {
+		return ""
+	}
+	b, err := url.Parse(base)
+	if err != nil {
+		return ""
+	}
+	r, err := url.Parse(ref)
+	if err != nil {
+		return ""
+	}
+	u := b.ResolveReference(r)
+	if u.Host == "" {
+		return ""
+	}
+	return u.String()
+}
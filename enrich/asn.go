@@ -0,0 +1,131 @@
+package enrich
+
+import (
+	"bufio"
+	"compress/gzip"
+	"fmt"
+	"io"
+	"net"
+	"net/netip"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/oschwald/maxminddb-golang"
+)
+
+// ASN is the autonomous system announcing an address.
+type ASN struct {
+	Number  int
+	Org     string
+	Country string
+}
+
+// ASNDB looks up the autonomous system of an address.
+type ASNDB interface {
+	Lookup(addr netip.Addr) (ASN, bool)
+	Close() error
+}
+
+// OpenASNDB opens an offline ASN database: a MaxMind-format .mmdb file
+// (GeoLite2-ASN or compatible) or an iptoasn.com TSV file, optionally
+// gzip-compressed.
+func OpenASNDB(path string) (ASNDB, error) {
+	if strings.HasSuffix(path, ".mmdb") {
+		r, err := maxminddb.Open(path)
+		if err != nil {
+			return nil, err
+		}
+		return &mmdbASN{r}, nil
+	}
+	return loadTSV(path)
+}
+
+type mmdbASN struct {
+	r *maxminddb.Reader
+}
+
+func (m *mmdbASN) Lookup(addr netip.Addr) (ASN, bool) {
+	var rec struct {
+		Number uint   `maxminddb:"autonomous_system_number"`
+		Org    string `maxminddb:"autonomous_system_organization"`
+	}
+	if err := m.r.Lookup(net.IP(addr.AsSlice()), &rec); err != nil || rec.Number == 0 {
+		return ASN{}, false
+	}
+	return ASN{Number: int(rec.Number), Org: rec.Org}, true
+}
+
+func (m *mmdbASN) Close() error {
+	return m.r.Close()
+}
+
+type asnRange struct {
+	start, end netip.Addr
+	asn        ASN
+}
+
+// tsvASN holds the ranges of an iptoasn TSV file, sorted by start address.
+type tsvASN struct {
+	ranges []asnRange
+}
+
+// loadTSV reads an iptoasn.com file (ip2asn-v4.tsv, ip2asn-combined.tsv):
+// range start, range end, AS number, country code and AS description.
+// Ranges with AS number 0 are not routed and skipped.
+func loadTSV(path string) (*tsvASN, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	var r io.Reader = f
+	if strings.HasSuffix(path, ".gz") {
+		zr, err := gzip.NewReader(f)
+		if err != nil {
+			return nil, err
+		}
+		defer zr.Close()
+		r = zr
+	}
+
+	db := &tsvASN{}
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		fields := strings.Split(scanner.Text(), "\t")
+		if len(fields) < 5 {
+			continue
+		}
+		start, err1 := netip.ParseAddr(fields[0])
+		end, err2 := netip.ParseAddr(fields[1])
+		number, err3 := strconv.Atoi(fields[2])
+		if err1 != nil || err2 != nil || err3 != nil || number == 0 {
+			continue
+		}
+		db.ranges = append(db.ranges, asnRange{start, end, ASN{Number: number, Country: fields[3], Org: fields[4]}})
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	if len(db.ranges) == 0 {
+		return nil, fmt.Errorf("%s: no ASN ranges found", path)
+	}
+
+	sort.Slice(db.ranges, func(i, j int) bool { return db.ranges[i].start.Less(db.ranges[j].start) })
+	return db, nil
+}
+
+func (t *tsvASN) Lookup(addr netip.Addr) (ASN, bool) {
+	addr = addr.Unmap()
+	i := sort.Search(len(t.ranges), func(i int) bool { return addr.Less(t.ranges[i].start) }) - 1
+	if i < 0 || t.ranges[i].end.Less(addr) {
+		return ASN{}, false
+	}
+	return t.ranges[i].asn, true
+}
+
+func (t *tsvASN) Close() error {
+	return nil
+}
@@ -0,0 +1,30 @@
+package enrich
+
+// knownASNs maps the autonomous systems of major cloud and CDN providers to
+// the provider, for providers that do not publish their ranges.
+var knownASNs = map[int]struct {
+	provider string
+	cdn      bool
+}{
+	13335:  {"cloudflare", true},
+	209242: {"cloudflare", true},
+	20940:  {"akamai", true},
+	16625:  {"akamai", true},
+	21342:  {"akamai", true},
+	54113:  {"fastly", true},
+	19551:  {"incapsula", true},
+	60068:  {"cdn77", true},
+	22822:  {"edgio", true},
+	15133:  {"edgio", true},
+	16509:  {"aws", false},
+	14618:  {"aws", false},
+	15169:  {"gcp", false},
+	396982: {"gcp", false},
+	8075:   {"azure", false},
+	31898:  {"oracle", false},
+	14061:  {"digitalocean", false},
+	63949:  {"linode", false},
+	20473:  {"vultr", false},
+	24940:  {"hetzner", false},
+	16276:  {"ovh", false},
+}
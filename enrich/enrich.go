@@ -0,0 +1,116 @@
+package enrich
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"net/netip"
+	"os"
+	"sort"
+
+	"github.com/itsmeashim/rdb/models"
+)
+
+// Enricher classifies IP addresses against local range and ASN datasets.
+type Enricher struct {
+	ranges *Ranges
+	asn    ASNDB
+
+	// Fingerprint identifies the dataset versions; results computed with
+	// a different fingerprint are stale.
+	Fingerprint string
+}
+
+// New loads the range files, keyed by provider, and the ASN database.
+// Either may be empty, but not both.
+func New(rangeFiles map[string]string, asnPath string) (*Enricher, error) {
+	if len(rangeFiles) == 0 && asnPath == "" {
+		return nil, fmt.Errorf("no datasets configured")
+	}
+
+	providers := make([]string, 0, len(rangeFiles))
+	for p := range rangeFiles {
+		providers = append(providers, p)
+	}
+	sort.Strings(providers)
+
+	h := sha256.New()
+	var all []Range
+	for _, p := range providers {
+		ranges, err := LoadRanges(p, rangeFiles[p])
+		if err != nil {
+			return nil, err
+		}
+		all = append(all, ranges...)
+		if err := stamp(h, p, rangeFiles[p]); err != nil {
+			return nil, err
+		}
+	}
+
+	e := &Enricher{ranges: NewRanges(all)}
+	if asnPath != "" {
+		db, err := OpenASNDB(asnPath)
+		if err != nil {
+			return nil, err
+		}
+		e.asn = db
+		if err := stamp(h, "asn", asnPath); err != nil {
+			return nil, err
+		}
+	}
+	e.Fingerprint = hex.EncodeToString(h.Sum(nil))[:16]
+	return e, nil
+}
+
+// stamp adds a dataset to the fingerprint by name, path, size and
+// modification time, so refreshed files invalidate earlier results without
+// hashing their contents.
+func stamp(w io.Writer, name, path string) error {
+	info, err := os.Stat(path)
+	if err != nil {
+		return err
+	}
+	fmt.Fprintf(w, "%s\x00%s\x00%d\x00%d\n", name, path, info.Size(), info.ModTime().UnixNano())
+	return nil
+}
+
+// Close releases the ASN database.
+func (e *Enricher) Close() error {
+	if e.asn != nil {
+		return e.asn.Close()
+	}
+	return nil
+}
+
+// Classify returns what the datasets know about ip. The cloud provider comes
+// from the published ranges, falling back to the well-known ASNs of
+// providers; an IP of neither is self-hosted and has an empty Cloud.
+func (e *Enricher) Classify(ip string) (models.IPInfo, bool) {
+	addr, err := netip.ParseAddr(ip)
+	if err != nil {
+		return models.IPInfo{}, false
+	}
+	info := models.IPInfo{IP: ip}
+
+	if rg, ok := e.ranges.Lookup(addr); ok {
+		info.Cloud = rg.Provider
+		info.Service = rg.Service
+		info.Region = rg.Region
+		info.CDN = rg.CDN()
+	}
+	if e.asn != nil {
+		if a, ok := e.asn.Lookup(addr); ok {
+			info.ASN = a.Number
+			info.ASOrg = a.Org
+			info.Country = a.Country
+			if known, ok := knownASNs[a.Number]; ok {
+				if info.Cloud == "" {
+					info.Cloud = known.provider
+				}
+				info.CDN = info.CDN || known.cdn
+			}
+		}
+	}
+	return info, true
+}
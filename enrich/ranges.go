@@ -0,0 +1,225 @@
+package enrich
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/netip"
+	"os"
+	"sort"
+	"strings"
+)
+
+// Range is a published IP range of a cloud or CDN provider.
+type Range struct {
+	Prefix   netip.Prefix
+	Provider string
+	Service  string
+	Region   string
+}
+
+// CDN reports whether the range serves a CDN or edge proxy rather than
+// customer workloads.
+func (r Range) CDN() bool {
+	return cdnProviders[r.Provider] || cdnServices[strings.ToLower(r.Service)]
+}
+
+var cdnProviders = map[string]bool{
+	"cloudflare": true,
+	"akamai":     true,
+	"fastly":     true,
+	"incapsula":  true,
+	"cdn77":      true,
+	"edgio":      true,
+}
+
+var cdnServices = map[string]bool{
+	"cloudfront":              true,
+	"azurefrontdoor.frontend": true,
+	"azurecdn":                true,
+}
+
+// genericServices cover the whole address space of a provider and are
+// listed again under the specific service, which is preferred.
+var genericServices = map[string]bool{
+	"amazon":     true,
+	"azurecloud": true,
+}
+
+// generic reports whether service is a generic one, including its regional
+// Azure tags such as AzureCloud.eastus.
+func generic(service string) bool {
+	name, _, _ := strings.Cut(strings.ToLower(service), ".")
+	return genericServices[name]
+}
+
+// rangeFile holds the fields of every supported range file layout.
+type rangeFile struct {
+	// AWS ip-ranges.json
+	Prefixes []struct {
+		IPPrefix   string `json:"ip_prefix"`
+		IPv6Prefix string `json:"ipv6_prefix"`
+		// GCP cloud.json
+		IPv4Prefix string `json:"ipv4Prefix"`
+		GCPv6      string `json:"ipv6Prefix"`
+		Scope      string `json:"scope"`
+
+		Region  string `json:"region"`
+		Service string `json:"service"`
+	} `json:"prefixes"`
+	IPv6Prefixes []struct {
+		IPv6Prefix string `json:"ipv6_prefix"`
+		Region     string `json:"region"`
+		Service    string `json:"service"`
+	} `json:"ipv6_prefixes"`
+	// Azure ServiceTags_Public.json
+	Values []struct {
+		Name       string `json:"name"`
+		Properties struct {
+			Region          string   `json:"region"`
+			AddressPrefixes []string `json:"addressPrefixes"`
+		} `json:"properties"`
+	} `json:"values"`
+	// Oracle public_ip_ranges.json
+	Regions []struct {
+		Region string `json:"region"`
+		CIDRs  []struct {
+			CIDR string `json:"cidr"`
+		} `json:"cidrs"`
+	} `json:"regions"`
+	// Fastly public-ip-list
+	Addresses     []string `json:"addresses"`
+	IPv6Addresses []string `json:"ipv6_addresses"`
+}
+
+// LoadRanges reads the IP ranges of provider from path. JSON files in the
+// layouts published by AWS, GCP, Azure, Oracle and Fastly are recognized;
+// anything else is read as a list of CIDRs or IPs, one per line, as
+// published by Cloudflare and others (CSV lines use the first column).
+func LoadRanges(provider, path string) ([]Range, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	provider = strings.ToLower(provider)
+
+	var ranges []Range
+	add := func(prefix, service, region string) {
+		p, ok := parsePrefix(prefix)
+		if ok {
+			ranges = append(ranges, Range{Prefix: p, Provider: provider, Service: service, Region: region})
+		}
+	}
+
+	if trimmed := bytes.TrimSpace(data); len(trimmed) > 0 && trimmed[0] == '{' {
+		var f rangeFile
+		if err := json.Unmarshal(trimmed, &f); err != nil {
+			return nil, fmt.Errorf("%s: %w", path, err)
+		}
+		for _, p := range f.Prefixes {
+			region := p.Region
+			if region == "" {
+				region = p.Scope
+			}
+			for _, prefix := range []string{p.IPPrefix, p.IPv6Prefix, p.IPv4Prefix, p.GCPv6} {
+				if prefix != "" {
+					add(prefix, p.Service, region)
+				}
+			}
+		}
+		for _, p := range f.IPv6Prefixes {
+			add(p.IPv6Prefix, p.Service, p.Region)
+		}
+		// The tag name, unlike its systemService, tells Front Door's
+		// frontend (edge) ranges apart from its backend ones.
+		for _, v := range f.Values {
+			for _, prefix := range v.Properties.AddressPrefixes {
+				add(prefix, v.Name, v.Properties.Region)
+			}
+		}
+		for _, r := range f.Regions {
+			for _, c := range r.CIDRs {
+				add(c.CIDR, "", r.Region)
+			}
+		}
+		for _, prefix := range append(f.Addresses, f.IPv6Addresses...) {
+			add(prefix, "", "")
+		}
+	} else {
+		scanner := bufio.NewScanner(bytes.NewReader(data))
+		for scanner.Scan() {
+			line := strings.TrimSpace(scanner.Text())
+			if line == "" || strings.HasPrefix(line, "#") {
+				continue
+			}
+			field, _, _ := strings.Cut(line, ",")
+			add(strings.TrimSpace(field), "", "")
+		}
+	}
+
+	if len(ranges) == 0 {
+		return nil, fmt.Errorf("%s: no IP ranges found", path)
+	}
+	return ranges, nil
+}
+
+func parsePrefix(s string) (netip.Prefix, bool) {
+	if p, err := netip.ParsePrefix(s); err == nil {
+		return p.Masked(), true
+	}
+	if a, err := netip.ParseAddr(s); err == nil {
+		return netip.PrefixFrom(a, a.BitLen()), true
+	}
+	return netip.Prefix{}, false
+}
+
+// Ranges finds the most specific range containing an address.
+type Ranges struct {
+	byBits map[int]map[netip.Prefix]Range
+	bits   []int
+}
+
+// NewRanges indexes ranges by prefix. When ranges overlap exactly, a CDN
+// range wins over others and a specific service over a generic one.
+func NewRanges(list []Range) *Ranges {
+	r := &Ranges{byBits: map[int]map[netip.Prefix]Range{}}
+	for _, rg := range list {
+		bits := rg.Prefix.Bits()
+		if r.byBits[bits] == nil {
+			r.byBits[bits] = map[netip.Prefix]Range{}
+			r.bits = append(r.bits, bits)
+		}
+		if prev, ok := r.byBits[bits][rg.Prefix]; ok && !preferred(rg, prev) {
+			continue
+		}
+		r.byBits[bits][rg.Prefix] = rg
+	}
+	sort.Sort(sort.Reverse(sort.IntSlice(r.bits)))
+	return r
+}
+
+func preferred(a, b Range) bool {
+	if a.CDN() != b.CDN() {
+		return a.CDN()
+	}
+	return generic(b.Service) && !generic(a.Service)
+}
+
+// Lookup returns the most specific range containing addr.
+func (r *Ranges) Lookup(addr netip.Addr) (Range, bool) {
+	addr = addr.Unmap()
+	for _, bits := range r.bits {
+		if bits > addr.BitLen() {
+			continue
+		}
+		p, err := addr.Prefix(bits)
+		if err != nil {
+			continue
+		}
+		if rg, ok := r.byBits[bits][p]; ok {
+			return rg, true
+		}
+	}
+	return Range{}, false
+}
@@ -0,0 +1,88 @@
+package extract
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+
+	"github.com/itsmeashim/rdb/rulefile"
+)
+
+// Rule extracts values of one kind from response bodies. When Group is set
+// the value is that capture group instead of the whole match.
+type Rule struct {
+	Name    string `yaml:"name"`
+	Kind    string `yaml:"kind"`
+	Pattern string `yaml:"pattern"`
+	Group   int    `yaml:"group"`
+
+	re *regexp.Regexp
+}
+
+// Match is a single extracted value
+type Match struct {
+	Rule  string
+	Value string
+}
+
+// Rules returns the extraction rules: the built-in pack plus the rules of
+// the YAML file at path given with --rules. A file rule named like a
+// built-in one replaces its pattern, e.g. to tighten a noisy secret regex.
+func Rules(path string) ([]Rule, error) {
+	rules, err := rulefile.Load(path, builtin, func(r Rule) string { return r.Name }, func(r Rule) error {
+		if r.Name == "" || r.Kind == "" || r.Pattern == "" {
+			return fmt.Errorf("name, kind and pattern are required")
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	for i := range rules {
+		re, err := regexp.Compile(rules[i].Pattern)
+		if err != nil {
+			return nil, fmt.Errorf("rule %q: %w", rules[i].Name, err)
+		}
+		if rules[i].Group > re.NumSubexp() {
+			return nil, fmt.Errorf("rule %q: group %d does not exist", rules[i].Name, rules[i].Group)
+		}
+		rules[i].re = re
+	}
+	return rules, nil
+}
+
+// Kinds returns the distinct rule kinds, sorted.
+func Kinds(rules []Rule) []string {
+	seen := map[string]bool{}
+	var kinds []string
+	for _, r := range rules {
+		if !seen[r.Kind] {
+			seen[r.Kind] = true
+			kinds = append(kinds, r.Kind)
+		}
+	}
+	sort.Strings(kinds)
+	return kinds
+}
+
+// Find runs the rules of kind over body and returns the distinct values,
+// each attributed to the first rule that matched it.
+func Find(rules []Rule, kind string, body []byte) []Match {
+	seen := map[string]bool{}
+	var matches []Match
+	for _, r := range rules {
+		if r.Kind != kind {
+			continue
+		}
+		for _, sub := range r.re.FindAllSubmatch(body, -1) {
+			m := Match{Rule: r.Name, Value: string(sub[r.Group])}
+			if m.Value == "" || seen[m.Value] {
+				continue
+			}
+			seen[m.Value] = true
+			matches = append(matches, m)
+		}
+	}
+	return matches
+}
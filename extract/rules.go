@@ -0,0 +1,36 @@
+package extract
+
+// builtin is the extraction rule pack, grouped by kind: provider token
+// formats and key-value secrets, endpoints, emails, S3 buckets and JS files.
+var builtin = []Rule{
+	// secrets
+	{Name: "aws-access-key-id", Kind: "secrets", Pattern: `\b(?:AKIA|ASIA)[0-9A-Z]{16}\b`},
+	{Name: "google-api-key", Kind: "secrets", Pattern: `\bAIza[0-9A-Za-z_\-]{35}\b`},
+	{Name: "github-token", Kind: "secrets", Pattern: `\bgh[pousr]_[A-Za-z0-9]{36,255}\b`},
+	{Name: "slack-token", Kind: "secrets", Pattern: `\bxox[abposr]-[A-Za-z0-9-]{10,}`},
+	{Name: "slack-webhook", Kind: "secrets", Pattern: `https://hooks\.slack\.com/services/T[A-Za-z0-9_]+/B[A-Za-z0-9_]+/[A-Za-z0-9_]+`},
+	{Name: "stripe-live-key", Kind: "secrets", Pattern: `\b(?:sk|rk)_live_[0-9A-Za-z]{24,}\b`},
+	{Name: "sendgrid-api-key", Kind: "secrets", Pattern: `\bSG\.[A-Za-z0-9_\-]{22}\.[A-Za-z0-9_\-]{43}\b`},
+	{Name: "mailgun-api-key", Kind: "secrets", Pattern: `\bkey-[0-9a-zA-Z]{32}\b`},
+	{Name: "twilio-api-key", Kind: "secrets", Pattern: `\bSK[0-9a-fA-F]{32}\b`},
+	{Name: "private-key", Kind: "secrets", Pattern: `-----BEGIN (?:RSA |EC |DSA |OPENSSH |PGP )?PRIVATE KEY(?: BLOCK)?-----`},
+	{Name: "jwt", Kind: "secrets", Pattern: `\beyJ[A-Za-z0-9_\-]{10,}\.eyJ[A-Za-z0-9_\-]{10,}\.[A-Za-z0-9_\-]{10,}`},
+	{Name: "generic-secret", Kind: "secrets", Group: 1,
+		Pattern: `(?i)(?:api[_-]?key|secret|access[_-]?token|auth[_-]?token|passw(?:or)?d)["']?\s*[:=]\s*["']([A-Za-z0-9_\-\.+/=]{12,})["']`},
+
+	// endpoints
+	{Name: "absolute-url", Kind: "endpoints", Pattern: `https?://[A-Za-z0-9.\-]+(?::\d+)?(?:/[^\s"'<>\\` + "`" + `)]*)?`},
+	{Name: "relative-path", Kind: "endpoints", Group: 1, Pattern: `["'` + "`" + `](/[A-Za-z0-9_\-]+(?:/[A-Za-z0-9_\-.{}:]+)+/?(?:\?[^"'` + "`" + `\s<>]*)?)["'` + "`" + `]`},
+
+	// emails
+	{Name: "email", Kind: "emails", Pattern: `\b[A-Za-z0-9._%+\-]+@[A-Za-z0-9.\-]+\.[A-Za-z]{2,24}\b`},
+
+	// s3-buckets
+	{Name: "s3-virtual-host", Kind: "s3-buckets", Group: 1, Pattern: `\b([a-z0-9][a-z0-9.\-]{1,61}[a-z0-9])\.s3(?:[.\-][a-z0-9\-]+)?\.amazonaws\.com`},
+	{Name: "s3-path-style", Kind: "s3-buckets", Group: 1, Pattern: `\bs3(?:[.\-][a-z0-9\-]+)?\.amazonaws\.com/([a-z0-9][a-z0-9.\-]{1,61}[a-z0-9])`},
+	{Name: "s3-uri", Kind: "s3-buckets", Group: 1, Pattern: `\bs3://([a-z0-9][a-z0-9.\-]{1,61}[a-z0-9])`},
+
+	// js-files
+	{Name: "script-src", Kind: "js-files", Group: 1, Pattern: `(?i)<script[^>]+src\s*=\s*["']([^"']+)["']`},
+	{Name: "js-reference", Kind: "js-files", Group: 1, Pattern: `["'` + "`" + `]([^"'` + "`" + `\s<>]+\.js(?:\?[^"'` + "`" + `\s<>]*)?)["'` + "`" + `]`},
+}
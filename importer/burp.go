@@ -0,0 +1,115 @@
+package importer
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/base64"
+	"encoding/xml"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/itsmeashim/rdb/models"
+)
+
+type burpItem struct {
+	URL  string `xml:"url"`
+	Host struct {
+		Name string `xml:",chardata"`
+		IP   string `xml:"ip,attr"`
+	} `xml:"host"`
+	Port     string `xml:"port"`
+	Protocol string `xml:"protocol"`
+	Method   string `xml:"method"`
+	Path     string `xml:"path"`
+	Status   string `xml:"status"`
+	Response struct {
+		Data   string `xml:",chardata"`
+		Base64 bool   `xml:"base64,attr"`
+	} `xml:"response"`
+}
+
+// readBurp reads a Burp Suite sitemap or proxy history export ("Save
+// items" as XML). Items are decoded one at a time since exports with
+// responses included get large.
+func readBurp(r io.Reader, fn func(models.HTTPXData) error) error {
+	dec := xml.NewDecoder(r)
+	for {
+		tok, err := dec.Token()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		start, ok := tok.(xml.StartElement)
+		if !ok || start.Name.Local != "item" {
+			continue
+		}
+
+		var item burpItem
+		if err := dec.DecodeElement(&item, &start); err != nil {
+			return err
+		}
+		d, ok := fromURL(strings.TrimSpace(item.URL))
+		if !ok {
+			continue
+		}
+		d.Host = strings.TrimSpace(item.Host.Name)
+		if item.Host.IP != "" {
+			d.A = models.StringArray{item.Host.IP}
+		}
+		if item.Port != "" {
+			d.Port = item.Port
+		}
+		if item.Protocol != "" {
+			d.Scheme = item.Protocol
+		}
+		if item.Path != "" {
+			d.Path = item.Path
+		}
+		d.Method = item.Method
+		d.StatusCode, _ = strconv.Atoi(item.Status)
+
+		raw := []byte(item.Response.Data)
+		if item.Response.Base64 {
+			if raw, err = base64.StdEncoding.DecodeString(strings.TrimSpace(item.Response.Data)); err != nil {
+				continue
+			}
+		}
+		if len(raw) > 0 {
+			applyResponse(&d, raw)
+		}
+		if err := fn(d); err != nil {
+			return err
+		}
+	}
+}
+
+// applyResponse fills the response fields of d from a raw HTTP response.
+// Responses that do not parse leave d as it is.
+func applyResponse(d *models.HTTPXData, raw []byte) {
+	resp, err := http.ReadResponse(bufio.NewReader(bytes.NewReader(raw)), nil)
+	if err != nil {
+		return
+	}
+	defer resp.Body.Close()
+	// A truncated body still carries the parts we want.
+	body, _ := io.ReadAll(resp.Body)
+
+	d.StatusCode = resp.StatusCode
+	d.Headers = models.Headers{}
+	for name, values := range resp.Header {
+		d.Headers[models.HeaderKey(name)] = strings.Join(values, ", ")
+	}
+	d.ContentType = strings.TrimSpace(strings.Split(resp.Header.Get("Content-Type"), ";")[0])
+	d.Webserver = resp.Header.Get("Server")
+	d.Location = resp.Header.Get("Location")
+
+	d.Body = string(body)
+	d.ContentLength = len(body)
+	d.Words = len(strings.Fields(d.Body))
+	d.Lines = strings.Count(d.Body, "\n") + 1
+	d.Title = strings.ToValidUTF8(title(d.Body), "")
+}
@@ -0,0 +1,46 @@
+package importer
+
+import (
+	"encoding/json"
+	"io"
+
+	"github.com/itsmeashim/rdb/models"
+)
+
+type ffufOutput struct {
+	Results []struct {
+		URL              string `json:"url"`
+		Status           int    `json:"status"`
+		Length           int    `json:"length"`
+		Words            int    `json:"words"`
+		Lines            int    `json:"lines"`
+		ContentType      string `json:"content-type"`
+		RedirectLocation string `json:"redirectlocation"`
+	} `json:"results"`
+}
+
+// readFfuf reads ffuf JSON output (-of json).
+func readFfuf(r io.Reader, fn func(models.HTTPXData) error) error {
+	var out ffufOutput
+	if err := json.NewDecoder(r).Decode(&out); err != nil {
+		return err
+	}
+
+	for _, res := range out.Results {
+		d, ok := fromURL(res.URL)
+		if !ok {
+			continue
+		}
+		d.Method = "GET"
+		d.StatusCode = res.Status
+		d.ContentLength = res.Length
+		d.Words = res.Words
+		d.Lines = res.Lines
+		d.ContentType = res.ContentType
+		d.Location = res.RedirectLocation
+		if err := fn(d); err != nil {
+			return err
+		}
+	}
+	return nil
+}
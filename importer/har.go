@@ -0,0 +1,121 @@
+package importer
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/itsmeashim/rdb/models"
+)
+
+type harFile struct {
+	Log struct {
+		Entries []struct {
+			StartedDateTime time.Time `json:"startedDateTime"`
+			Request         struct {
+				Method   string      `json:"method"`
+				URL      string      `json:"url"`
+				Headers  []harHeader `json:"headers"`
+				PostData *struct {
+					Text string `json:"text"`
+				} `json:"postData"`
+			} `json:"request"`
+			Response struct {
+				Status  int         `json:"status"`
+				Headers []harHeader `json:"headers"`
+				Content struct {
+					MimeType string `json:"mimeType"`
+					Text     string `json:"text"`
+					Encoding string `json:"encoding"`
+				} `json:"content"`
+			} `json:"response"`
+		} `json:"entries"`
+	} `json:"log"`
+}
+
+type harHeader struct {
+	Name  string `json:"name"`
+	Value string `json:"value"`
+}
+
+// ReadRequests parses r as format and calls fn for every request/response
+// pair found. Source is set to the format name.
+func ReadRequests(format string, r io.Reader, fn func(models.HTTPRequest) error) error {
+	switch format {
+	case "har":
+		return readHAR(r, func(req models.HTTPRequest) error {
+			req.Source = format
+			return fn(req)
+		})
+	}
+	return fmt.Errorf("unknown request import format %q", format)
+}
+
+// readHAR reads an HTTP Archive, as exported by browsers and proxies.
+// HTTP/2 pseudo-headers such as :authority are dropped.
+func readHAR(r io.Reader, fn func(models.HTTPRequest) error) error {
+	var har harFile
+	if err := json.NewDecoder(r).Decode(&har); err != nil {
+		return err
+	}
+
+	for _, e := range har.Log.Entries {
+		u, err := url.Parse(e.Request.URL)
+		if err != nil || u.Hostname() == "" {
+			continue
+		}
+		req := models.HTTPRequest{
+			Method:          strings.ToUpper(e.Request.Method),
+			URL:             e.Request.URL,
+			Hostname:        strings.ToLower(u.Hostname()),
+			Path:            u.EscapedPath(),
+			RequestHeaders:  harHeaders(e.Request.Headers),
+			StatusCode:      e.Response.Status,
+			ResponseHeaders: harHeaders(e.Response.Headers),
+			ContentType:     strings.TrimSpace(strings.Split(e.Response.Content.MimeType, ";")[0]),
+			ResponseBody:    e.Response.Content.Text,
+			StartedAt:       e.StartedDateTime,
+		}
+		if req.Path == "" {
+			req.Path = "/"
+		}
+		if e.Request.PostData != nil {
+			req.RequestBody = e.Request.PostData.Text
+		}
+		if e.Response.Content.Encoding == "base64" {
+			body, err := base64.StdEncoding.DecodeString(e.Response.Content.Text)
+			if err != nil {
+				continue
+			}
+			req.ResponseBody = string(body)
+		}
+		if err := fn(req); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// harHeaders converts HAR headers to a map keyed by the names as sent, so
+// requests can be replayed, joining repeated headers with ", ".
+func harHeaders(list []harHeader) models.Headers {
+	if len(list) == 0 {
+		return nil
+	}
+	h := models.Headers{}
+	for _, hdr := range list {
+		if strings.HasPrefix(hdr.Name, ":") {
+			continue
+		}
+		if prev, ok := h[hdr.Name].(string); ok {
+			h[hdr.Name] = prev + ", " + hdr.Value
+		} else {
+			h[hdr.Name] = hdr.Value
+		}
+	}
+	return h
+}
@@ -0,0 +1,129 @@
+package importer
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"net"
+	"net/url"
+	"regexp"
+	"strings"
+
+	"github.com/itsmeashim/rdb/models"
+)
+
+// Formats are the supported import formats. gau and wayback are plain URL
+// lists that differ only in the source they are attributed to.
+var Formats = []string{"nmap", "masscan", "ffuf", "gau", "wayback", "burp"}
+
+// RequestFormats are the import formats of captured traffic, read with
+// ReadRequests into request/response pairs rather than records.
+var RequestFormats = []string{"har"}
+
+// Static reports whether records of format describe historic data that does
+// not change between imports, so URLs already imported can be skipped.
+func Static(format string) bool {
+	return format == "gau" || format == "wayback"
+}
+
+// Read parses r as format and calls fn for every record found. Records carry
+// the fields the format provides; Source is set to the format name.
+func Read(format string, r io.Reader, fn func(models.HTTPXData) error) error {
+	emit := func(d models.HTTPXData) error {
+		d.Source = format
+		return fn(d)
+	}
+	switch format {
+	case "nmap":
+		return readNmap(r, emit)
+	case "masscan":
+		return readMasscan(r, emit)
+	case "ffuf":
+		return readFfuf(r, emit)
+	case "gau", "wayback":
+		return readURLs(r, emit)
+	case "burp":
+		return readBurp(r, emit)
+	}
+	return fmt.Errorf("unknown import format %q", format)
+}
+
+// readURLs reads one URL per line, as written by gau and waybackurls.
+func readURLs(r io.Reader, fn func(models.HTTPXData) error) error {
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		d, ok := fromURL(line)
+		if !ok {
+			continue
+		}
+		if err := fn(d); err != nil {
+			return err
+		}
+	}
+	return scanner.Err()
+}
+
+// fromURL returns a record with the URL fields of rawURL filled in the way
+// httpx fills them. It reports false for URLs without a host.
+func fromURL(rawURL string) (models.HTTPXData, bool) {
+	u, err := url.Parse(rawURL)
+	if err != nil || u.Hostname() == "" {
+		return models.HTTPXData{}, false
+	}
+	d := models.HTTPXData{
+		URL:    rawURL,
+		Input:  u.Hostname(),
+		Host:   u.Hostname(),
+		Scheme: u.Scheme,
+		Port:   u.Port(),
+		Path:   u.EscapedPath(),
+	}
+	if d.Port == "" {
+		d.Port = defaultPort(u.Scheme)
+	}
+	if d.Path == "" {
+		d.Path = "/"
+	}
+	if ip := net.ParseIP(d.Host); ip != nil {
+		d.A = models.StringArray{ip.String()}
+	}
+	return d, true
+}
+
+// serviceURL builds a URL for a network service found by a port scanner,
+// e.g. ssh://203.0.113.5:22. Default web ports are left out, as httpx does.
+func serviceURL(scheme, host, port string) string {
+	if port == defaultPort(scheme) {
+		if strings.Contains(host, ":") {
+			host = "[" + host + "]"
+		}
+		return scheme + "://" + host
+	}
+	return scheme + "://" + net.JoinHostPort(host, port)
+}
+
+func defaultPort(scheme string) string {
+	switch scheme {
+	case "https":
+		return "443"
+	case "http":
+		return "80"
+	}
+	return ""
+}
+
+var titleRe = regexp.MustCompile(`(?is)<title[^>]*>(.*?)</title>`)
+
+// title returns the HTML title of body, with whitespace collapsed.
+func title(body string) string {
+	m := titleRe.FindStringSubmatch(body)
+	if m == nil {
+		return ""
+	}
+	return strings.Join(strings.Fields(m[1]), " ")
+}
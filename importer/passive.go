@@ -0,0 +1,173 @@
+package importer
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/itsmeashim/rdb/models"
+)
+
+// ObservationFormats are the import formats of passive data, read with
+// ReadObservations into subdomain and DNS history rather than records.
+var ObservationFormats = []string{"crtsh", "pdns-json"}
+
+// ReadObservations parses r as format and calls fn for every name or DNS
+// record found. Source is set to the format name.
+func ReadObservations(format string, r io.Reader, fn func(models.DNSObservation) error) error {
+	emit := func(o models.DNSObservation) error {
+		o.Source = format
+		return fn(o)
+	}
+	switch format {
+	case "crtsh":
+		return readCrtsh(r, emit)
+	case "pdns-json":
+		return readPDNS(r, emit)
+	}
+	return fmt.Errorf("unknown observation import format %q", format)
+}
+
+type crtshEntry struct {
+	CommonName     string `json:"common_name"`
+	NameValue      string `json:"name_value"`
+	EntryTimestamp string `json:"entry_timestamp"`
+	NotBefore      string `json:"not_before"`
+}
+
+// readCrtsh reads the JSON output of crt.sh (?output=json). Every name of a
+// certificate is an observation dated by when the certificate was logged.
+func readCrtsh(r io.Reader, fn func(models.DNSObservation) error) error {
+	var entries []crtshEntry
+	if err := json.NewDecoder(r).Decode(&entries); err != nil {
+		return err
+	}
+
+	for _, e := range entries {
+		seen := crtshTime(e.EntryTimestamp)
+		if seen.IsZero() {
+			seen = crtshTime(e.NotBefore)
+		}
+		names := strings.Split(e.NameValue, "\n")
+		names = append(names, e.CommonName)
+		for _, name := range names {
+			host := normalizeName(name)
+			if host == "" {
+				continue
+			}
+			if err := fn(models.DNSObservation{Host: host, FirstSeen: seen, LastSeen: seen}); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// crtshTime parses crt.sh timestamps, which are UTC without a zone.
+func crtshTime(s string) time.Time {
+	for _, layout := range []string{"2006-01-02T15:04:05.999999999", time.RFC3339Nano} {
+		if t, err := time.Parse(layout, s); err == nil {
+			return t
+		}
+	}
+	return time.Time{}
+}
+
+type pdnsEntry struct {
+	RRName        string          `json:"rrname"`
+	RRType        string          `json:"rrtype"`
+	RData         json.RawMessage `json:"rdata"`
+	TimeFirst     int64           `json:"time_first"`
+	TimeLast      int64           `json:"time_last"`
+	ZoneTimeFirst int64           `json:"zone_time_first"`
+	ZoneTimeLast  int64           `json:"zone_time_last"`
+}
+
+// readPDNS reads passive DNS in the Passive DNS Common Output Format used
+// by Farsight DNSDB, CIRCL and others: one JSON object per line (or a JSON
+// array of them), with rdata a string or a list of strings.
+func readPDNS(r io.Reader, fn func(models.DNSObservation) error) error {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return err
+	}
+
+	var entries []pdnsEntry
+	if trimmed := bytes.TrimSpace(data); len(trimmed) > 0 && trimmed[0] == '[' {
+		if err := json.Unmarshal(trimmed, &entries); err != nil {
+			return err
+		}
+	} else {
+		dec := json.NewDecoder(bytes.NewReader(data))
+		for dec.More() {
+			var e pdnsEntry
+			if err := dec.Decode(&e); err != nil {
+				return err
+			}
+			entries = append(entries, e)
+		}
+	}
+
+	for _, e := range entries {
+		host := normalizeName(e.RRName)
+		if host == "" {
+			continue
+		}
+		first, last := e.TimeFirst, e.TimeLast
+		if first == 0 {
+			first, last = e.ZoneTimeFirst, e.ZoneTimeLast
+		}
+		o := models.DNSObservation{
+			Host:      host,
+			Type:      strings.ToUpper(e.RRType),
+			FirstSeen: unixTime(first),
+			LastSeen:  unixTime(last),
+		}
+
+		values := rdataValues(e.RData)
+		if len(values) == 0 {
+			values = []string{""}
+		}
+		for _, value := range values {
+			o.Value = strings.TrimSuffix(value, ".")
+			if err := fn(o); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// unixTime converts epoch seconds, returning the zero time for 0.
+func unixTime(sec int64) time.Time {
+	if sec == 0 {
+		return time.Time{}
+	}
+	return time.Unix(sec, 0).UTC()
+}
+
+func rdataValues(raw json.RawMessage) []string {
+	var one string
+	if err := json.Unmarshal(raw, &one); err == nil {
+		return []string{one}
+	}
+	var many []string
+	json.Unmarshal(raw, &many)
+	return many
+}
+
+// normalizeName lowercases a DNS name and strips the trailing dot and a
+// leading wildcard label. It returns "" for values that are not host
+// names, such as the e-mail addresses found in certificate subjects.
+func normalizeName(name string) string {
+	name = strings.ToLower(strings.TrimSpace(name))
+	name = strings.TrimSuffix(name, ".")
+	name = strings.TrimPrefix(name, "*.")
+	if name == "" || !strings.Contains(name, ".") || strings.ContainsAny(name, "@ /*:") {
+		return ""
+	}
+	return name
+}
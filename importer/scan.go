@@ -0,0 +1,173 @@
+package importer
+
+import (
+	"bufio"
+	"encoding/json"
+	"encoding/xml"
+	"io"
+	"strconv"
+	"strings"
+
+	"github.com/itsmeashim/rdb/models"
+)
+
+type nmapRun struct {
+	Hosts []struct {
+		Addresses []struct {
+			Addr     string `xml:"addr,attr"`
+			AddrType string `xml:"addrtype,attr"`
+		} `xml:"address"`
+		Hostnames []struct {
+			Name string `xml:"name,attr"`
+			Type string `xml:"type,attr"`
+		} `xml:"hostnames>hostname"`
+		Ports []struct {
+			Protocol string `xml:"protocol,attr"`
+			PortID   string `xml:"portid,attr"`
+			State    struct {
+				State string `xml:"state,attr"`
+			} `xml:"state"`
+			Service struct {
+				Name    string `xml:"name,attr"`
+				Product string `xml:"product,attr"`
+				Version string `xml:"version,attr"`
+				Tunnel  string `xml:"tunnel,attr"`
+			} `xml:"service"`
+			Scripts []struct {
+				ID     string `xml:"id,attr"`
+				Output string `xml:"output,attr"`
+			} `xml:"script"`
+		} `xml:"ports>port"`
+	} `xml:"host"`
+}
+
+// readNmap reads nmap XML output (-oX), emitting one record per open port.
+// The record host is the user-supplied hostname when nmap knows one, so
+// results line up with httpx records of the same name.
+func readNmap(r io.Reader, fn func(models.HTTPXData) error) error {
+	var run nmapRun
+	if err := xml.NewDecoder(r).Decode(&run); err != nil {
+		return err
+	}
+
+	for _, h := range run.Hosts {
+		var ip, host string
+		for _, a := range h.Addresses {
+			if a.AddrType == "ipv4" || a.AddrType == "ipv6" {
+				ip = a.Addr
+				break
+			}
+		}
+		for _, n := range h.Hostnames {
+			if host == "" || n.Type == "user" {
+				host = n.Name
+			}
+		}
+		if host == "" {
+			host = ip
+		}
+		if host == "" {
+			continue
+		}
+
+		for _, p := range h.Ports {
+			if p.State.State != "open" {
+				continue
+			}
+			scheme := p.Service.Name
+			if scheme == "http" && p.Service.Tunnel == "ssl" {
+				scheme = "https"
+			}
+			if scheme == "" || scheme == "unknown" {
+				scheme = p.Protocol
+			}
+
+			d := models.HTTPXData{
+				URL:    serviceURL(scheme, host, p.PortID),
+				Input:  host,
+				Host:   host,
+				Scheme: scheme,
+				Port:   p.PortID,
+			}
+			if ip != "" {
+				d.A = models.StringArray{ip}
+			}
+			if p.Service.Product != "" {
+				tech := p.Service.Product
+				if p.Service.Version != "" {
+					tech += ":" + p.Service.Version
+				}
+				d.Tech = models.StringArray{tech}
+				if strings.HasPrefix(scheme, "http") {
+					d.Webserver = p.Service.Product
+				}
+			}
+			for _, s := range p.Scripts {
+				if s.ID == "http-title" {
+					d.Title = strings.TrimSpace(s.Output)
+				}
+			}
+			if err := fn(d); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+type masscanResult struct {
+	IP    string `json:"ip"`
+	Ports []struct {
+		Port    int    `json:"port"`
+		Proto   string `json:"proto"`
+		Status  string `json:"status"`
+		Service struct {
+			Name   string `json:"name"`
+			Banner string `json:"banner"`
+		} `json:"service"`
+	} `json:"ports"`
+}
+
+// readMasscan reads masscan JSON output (-oJ). masscan writes one object per
+// line wrapped in a JSON array with trailing commas, and older versions leave
+// the array unterminated, so lines are decoded one at a time. Lines that are
+// not a host object are skipped.
+func readMasscan(r io.Reader, fn func(models.HTTPXData) error) error {
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		line = strings.TrimSuffix(line, ",")
+		if line == "" || line == "[" || line == "]" {
+			continue
+		}
+
+		// Status lines such as older versions' {finished: 1} hold no host.
+		var res masscanResult
+		if err := json.Unmarshal([]byte(line), &res); err != nil || res.IP == "" {
+			continue
+		}
+		for _, p := range res.Ports {
+			if p.Status != "" && p.Status != "open" {
+				continue
+			}
+			scheme := p.Proto
+			if p.Service.Name != "" {
+				scheme = p.Service.Name
+			}
+			port := strconv.Itoa(p.Port)
+			d := models.HTTPXData{
+				URL:    serviceURL(scheme, res.IP, port),
+				Input:  res.IP,
+				Host:   res.IP,
+				Scheme: scheme,
+				Port:   port,
+				A:      models.StringArray{res.IP},
+			}
+			if err := fn(d); err != nil {
+				return err
+			}
+		}
+	}
+	return scanner.Err()
+}
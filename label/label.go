@@ -0,0 +1,152 @@
+package label
+
+import (
+	"fmt"
+	"regexp"
+	"slices"
+	"strings"
+
+	"github.com/itsmeashim/rdb/models"
+	"github.com/itsmeashim/rdb/rulefile"
+)
+
+// Rule attaches Labels to every record matching all of its conditions.
+// Conditions are case-insensitive regular expressions; Tech matches when any
+// technology matches, and a header with an empty pattern only has to be
+// present.
+type Rule struct {
+	Name        string            `yaml:"name"`
+	Labels      []string          `yaml:"labels"`
+	Title       string            `yaml:"title"`
+	Tech        string            `yaml:"tech"`
+	Webserver   string            `yaml:"webserver"`
+	Path        string            `yaml:"path"`
+	ContentType string            `yaml:"content_type"`
+	Host        string            `yaml:"host"`
+	Status      []int             `yaml:"status"`
+	Headers     map[string]string `yaml:"headers"`
+
+	title, tech, webserver, path, contentType, host *regexp.Regexp
+	headers                                         map[string]*regexp.Regexp
+}
+
+// Rules returns the labeling rules: the built-in ones plus those of the
+// YAML file at path, set with 'rdb config --label-rules'. A file rule named
+// like a built-in one changes what that built-in label matches.
+func Rules(path string) ([]Rule, error) {
+	rules, err := rulefile.Load(path, builtin, func(r Rule) string { return r.Name }, func(r Rule) error {
+		if r.Name == "" || len(r.Labels) == 0 {
+			return fmt.Errorf("name and labels are required")
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	for i := range rules {
+		if err := rules[i].compile(); err != nil {
+			return nil, fmt.Errorf("rule %q: %w", rules[i].Name, err)
+		}
+	}
+	return rules, nil
+}
+
+func (r *Rule) compile() error {
+	var err error
+	for _, c := range []struct {
+		pattern string
+		re      **regexp.Regexp
+	}{
+		{r.Title, &r.title}, {r.Tech, &r.tech}, {r.Webserver, &r.webserver},
+		{r.Path, &r.path}, {r.ContentType, &r.contentType}, {r.Host, &r.host},
+	} {
+		if c.pattern == "" {
+			continue
+		}
+		if *c.re, err = regexp.Compile("(?i)" + c.pattern); err != nil {
+			return err
+		}
+	}
+
+	r.headers = make(map[string]*regexp.Regexp, len(r.Headers))
+	for name, pattern := range r.Headers {
+		var re *regexp.Regexp
+		if pattern != "" {
+			if re, err = regexp.Compile("(?i)" + pattern); err != nil {
+				return err
+			}
+		}
+		r.headers[models.HeaderKey(name)] = re
+	}
+
+	if r.title == nil && r.tech == nil && r.webserver == nil && r.path == nil &&
+		r.contentType == nil && r.host == nil && len(r.Status) == 0 && len(r.headers) == 0 {
+		return fmt.Errorf("at least one condition is required")
+	}
+	return nil
+}
+
+// Match reports whether d satisfies every condition of the rule.
+func (r *Rule) Match(d *models.HTTPXData) bool {
+	if r.title != nil && !r.title.MatchString(d.Title) {
+		return false
+	}
+	if r.webserver != nil && !r.webserver.MatchString(d.Webserver) {
+		return false
+	}
+	if r.path != nil && !r.path.MatchString(d.Path) {
+		return false
+	}
+	if r.contentType != nil && !r.contentType.MatchString(d.ContentType) {
+		return false
+	}
+	if r.host != nil && !r.host.MatchString(d.Hostname) {
+		return false
+	}
+	if len(r.Status) > 0 && !slices.Contains(r.Status, d.StatusCode) {
+		return false
+	}
+	if r.tech != nil && !slices.ContainsFunc(d.Tech, r.tech.MatchString) {
+		return false
+	}
+	for name, re := range r.headers {
+		value, ok := d.Headers[name]
+		if !ok {
+			return false
+		}
+		if re != nil && !re.MatchString(headerString(value)) {
+			return false
+		}
+	}
+	return true
+}
+
+// headerString flattens a header value, which httpx emits as a string or,
+// for repeated headers, an array.
+func headerString(v interface{}) string {
+	switch v := v.(type) {
+	case string:
+		return v
+	case []interface{}:
+		parts := make([]string, 0, len(v))
+		for _, p := range v {
+			parts = append(parts, fmt.Sprint(p))
+		}
+		return strings.Join(parts, ", ")
+	default:
+		return fmt.Sprint(v)
+	}
+}
+
+// Apply returns the sorted, distinct labels of every rule matching d.
+func Apply(rules []Rule, d *models.HTTPXData) models.StringArray {
+	labels := models.StringArray{}
+	for i := range rules {
+		if rules[i].Match(d) {
+			labels = append(labels, rules[i].Labels...)
+		}
+	}
+	slices.Sort(labels)
+	return slices.Compact(labels)
+}
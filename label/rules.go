@@ -0,0 +1,33 @@
+package label
+
+// builtin labels login panels, Jenkins and Grafana instances, admin
+// consoles, staging environments and WAF block pages.
+var builtin = []Rule{
+	// login-panel
+	{Name: "login-title", Labels: []string{"login-panel"}, Title: `\b(log ?in|sign ?in|single sign-on|authentication required)\b`},
+	{Name: "login-path", Labels: []string{"login-panel"}, Path: `/(login|signin|sign-in|sso|auth/login|users/sign_in)\b`, Status: []int{200}},
+	{Name: "basic-auth", Labels: []string{"login-panel"}, Status: []int{401}, Headers: map[string]string{"www-authenticate": `basic|digest|ntlm|negotiate`}},
+
+	// jenkins
+	{Name: "jenkins-tech", Labels: []string{"jenkins", "ci"}, Tech: `^jenkins\b`},
+	{Name: "jenkins-header", Labels: []string{"jenkins", "ci"}, Headers: map[string]string{"x-jenkins": ""}},
+	{Name: "jenkins-title", Labels: []string{"jenkins", "ci"}, Title: `\[jenkins\]`},
+
+	// grafana
+	{Name: "grafana-tech", Labels: []string{"grafana", "monitoring"}, Tech: `^grafana\b`},
+	{Name: "grafana-title", Labels: []string{"grafana", "monitoring"}, Title: `^grafana\b`},
+
+	// admin-console
+	{Name: "admin-title", Labels: []string{"admin-console"}, Title: `\b(admin(istrator|istration)?\s*(panel|console|dashboard|area|login)|control panel|phpmyadmin|webmin|cpanel|plesk)\b`},
+	{Name: "admin-path", Labels: []string{"admin-console"}, Path: `^/(admin|administrator|wp-admin|manager/html|console)(/|$)`, Status: []int{200, 401, 403}},
+
+	// staging
+	{Name: "staging-host", Labels: []string{"staging"}, Host: `(^|[.-])(stag(e|ing)?|dev(el)?|test(ing)?|qa|uat|pre-?prod|sandbox)\d*([.-]|$)`},
+
+	// waf-block
+	{Name: "waf-cloudflare", Labels: []string{"waf-block"}, Title: `^attention required! \| cloudflare`},
+	{Name: "waf-akamai", Labels: []string{"waf-block"}, Title: `^access denied$`, Webserver: `akamaighost`},
+	{Name: "waf-incapsula", Labels: []string{"waf-block"}, Status: []int{403}, Headers: map[string]string{"x-iinfo": ""}},
+	{Name: "waf-aws", Labels: []string{"waf-block"}, Title: `^403 forbidden$`, Webserver: `awselb|cloudfront`, Status: []int{403}},
+	{Name: "waf-generic", Labels: []string{"waf-block"}, Title: `request rejected|web application firewall|blocked by|sucuri website firewall`},
+}
@@ -0,0 +1,21 @@
+package models
+
+import "time"
+
+// Target scopes an annotation to a URL or to a hostname within a program.
+// Annotations are keyed by target rather than by record so they survive
+// re-stores.
+type Target struct {
+	Program string `json:"program"`
+	Scope   string `json:"scope"`
+	Value   string `json:"target"`
+}
+
+// Note is a free-text note left on a target
+type Note struct {
+	ID int64 `json:"id"`
+	Target
+	Author    string    `json:"author"`
+	Text      string    `json:"text"`
+	CreatedAt time.Time `json:"created_at"`
+}
@@ -0,0 +1,46 @@
+package models
+
+import "time"
+
+// TLSData is the certificate information emitted by httpx -tls-grab
+type TLSData struct {
+	SubjectCN   string            `json:"subject_cn,omitempty"`
+	SubjectDN   string            `json:"subject_dn,omitempty"`
+	SubjectAN   []string          `json:"subject_an,omitempty"`
+	IssuerCN    string            `json:"issuer_cn,omitempty"`
+	IssuerDN    string            `json:"issuer_dn,omitempty"`
+	IssuerOrg   []string          `json:"issuer_org,omitempty"`
+	Serial      string            `json:"serial,omitempty"`
+	NotBefore   string            `json:"not_before,omitempty"`
+	NotAfter    string            `json:"not_after,omitempty"`
+	SelfSigned  bool              `json:"self_signed,omitempty"`
+	Wildcard    bool              `json:"wildcard_certificate,omitempty"`
+	Fingerprint map[string]string `json:"fingerprint_hash,omitempty"`
+}
+
+// FingerprintSHA256 returns the certificate SHA-256 fingerprint, falling back
+// to SHA-1 for older httpx output
+func (t *TLSData) FingerprintSHA256() string {
+	if t == nil {
+		return ""
+	}
+	if fp := t.Fingerprint["sha256"]; fp != "" {
+		return fp
+	}
+	return t.Fingerprint["sha1"]
+}
+
+// Certificate is a stored TLS certificate with the hosts that served it
+type Certificate struct {
+	ID          int64      `json:"id"`
+	Fingerprint string     `json:"fingerprint"`
+	SubjectCN   string     `json:"subject_cn"`
+	SANs        []string   `json:"sans"`
+	IssuerCN    string     `json:"issuer_cn"`
+	IssuerOrg   string     `json:"issuer_org"`
+	NotBefore   *time.Time `json:"not_before"`
+	NotAfter    *time.Time `json:"not_after"`
+	SelfSigned  bool       `json:"self_signed"`
+	Hosts       []string   `json:"hosts"`
+	Programs    []string   `json:"programs"`
+}
@@ -0,0 +1,22 @@
+package models
+
+// Change types detected while storing records
+const (
+	ChangeNewHost = "new_host"
+	ChangeNewURL  = "new_url"
+	ChangeStatus  = "status_change"
+	ChangeNewTech = "new_tech"
+	ChangeTitle   = "title_change"
+)
+
+// Change is a difference between an incoming record and what was already
+// stored for its program. From and To hold the old and new status code,
+// title or technology depending on Type.
+type Change struct {
+	Type    string `json:"type"`
+	Program string `json:"program"`
+	Host    string `json:"host"`
+	URL     string `json:"url"`
+	From    string `json:"from,omitempty"`
+	To      string `json:"to,omitempty"`
+}
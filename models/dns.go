@@ -0,0 +1,39 @@
+package models
+
+import "time"
+
+// DNSObservation is a DNS record of a host seen over a period of time, e.g.
+// from a passive DNS export. An observation without Type only records that
+// the name exists, as certificate transparency logs do.
+type DNSObservation struct {
+	Program   string    `json:"program,omitempty"`
+	Host      string    `json:"host"`
+	Type      string    `json:"type,omitempty"`
+	Value     string    `json:"value,omitempty"`
+	Source    string    `json:"source"`
+	FirstSeen time.Time `json:"first_seen"`
+	LastSeen  time.Time `json:"last_seen"`
+}
+
+// Subdomain is a name known for a program from any source, with the period
+// it was seen in and whether it has a stored record
+type Subdomain struct {
+	Program    string    `json:"program"`
+	Hostname   string    `json:"hostname"`
+	RootDomain string    `json:"root_domain"`
+	Sources    []string  `json:"sources"`
+	FirstSeen  time.Time `json:"first_seen"`
+	LastSeen   time.Time `json:"last_seen"`
+	Probed     bool      `json:"probed"`
+}
+
+// DNSChange is a host that started resolving to new values of one record
+// type: Previous were seen before the change, Current since.
+type DNSChange struct {
+	Program   string    `json:"program"`
+	Host      string    `json:"host"`
+	Type      string    `json:"type"`
+	Previous  []string  `json:"previous"`
+	Current   []string  `json:"current"`
+	ChangedAt time.Time `json:"changed_at"`
+}
@@ -0,0 +1,14 @@
+package models
+
+// IPInfo is what the enrichment datasets know about an IP address. An empty
+// Cloud means the address belongs to no known cloud or CDN provider.
+type IPInfo struct {
+	IP      string `json:"ip"`
+	Cloud   string `json:"cloud,omitempty"`
+	Service string `json:"service,omitempty"`
+	Region  string `json:"region,omitempty"`
+	CDN     bool   `json:"cdn"`
+	ASN     int    `json:"asn,omitempty"`
+	ASOrg   string `json:"as_org,omitempty"`
+	Country string `json:"country,omitempty"`
+}
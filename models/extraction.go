@@ -0,0 +1,23 @@
+package models
+
+import "time"
+
+// BodyRef points at a stored response body and the record it came from
+type BodyRef struct {
+	RecordID int64
+	Program  string
+	Hash     string
+}
+
+// Extraction is a value mined from a stored response body
+type Extraction struct {
+	ID        int64     `json:"id"`
+	RecordID  int64     `json:"record_id"`
+	URL       string    `json:"url"`
+	Program   string    `json:"program"`
+	Kind      string    `json:"kind"`
+	Rule      string    `json:"rule"`
+	Value     string    `json:"value"`
+	Status    string    `json:"status"`
+	CreatedAt time.Time `json:"created_at"`
+}
@@ -4,6 +4,7 @@ import (
 	"database/sql/driver"
 	"encoding/json"
 	"errors"
+	"strings"
 )
 
 // StringArray is a custom type for PostgreSQL text arrays
@@ -28,6 +29,43 @@ func (a *StringArray) Scan(value interface{}) error {
 	return json.Unmarshal(bytes, a)
 }
 
+// Headers holds response headers as emitted by httpx -irh, keyed by
+// lowercased names with dashes replaced by underscores
+type Headers map[string]interface{}
+
+func (h Headers) Value() (driver.Value, error) {
+	if h == nil {
+		return nil, nil
+	}
+	return json.Marshal(h)
+}
+
+func (h *Headers) Scan(value interface{}) error {
+	if value == nil {
+		*h = nil
+		return nil
+	}
+	bytes, ok := value.([]byte)
+	if !ok {
+		return errors.New("failed to scan Headers")
+	}
+	return json.Unmarshal(bytes, h)
+}
+
+// Hashes holds the response hashes emitted by httpx -hash, e.g. body_sha256
+type Hashes map[string]interface{}
+
+// BodySHA256 returns the body_sha256 hash, or "" when not present
+func (h Hashes) BodySHA256() string {
+	s, _ := h["body_sha256"].(string)
+	return strings.ToLower(s)
+}
+
+// HeaderKey normalizes a header name the way httpx does
+func HeaderKey(name string) string {
+	return strings.ReplaceAll(strings.ToLower(strings.TrimSpace(name)), "-", "_")
+}
+
 // HTTPXData represents the httpx JSON output (excluding unwanted fields)
 type HTTPXData struct {
 	ID            int64       `json:"id,omitempty" db:"id"`
@@ -35,6 +73,9 @@ type HTTPXData struct {
 	URL           string      `json:"url" db:"url"`
 	Input         string      `json:"input" db:"input"`
 	Location      string      `json:"location" db:"location"`
+	Chain         Chain       `json:"chain,omitempty" db:"chain"`
+	FinalURL      string      `json:"final_url,omitempty" db:"-"`
+	RedirectURL   string      `json:"redirect_url,omitempty" db:"redirect_url"`
 	Title         string      `json:"title" db:"title"`
 	Scheme        string      `json:"scheme" db:"scheme"`
 	Webserver     string      `json:"webserver" db:"webserver"`
@@ -44,11 +85,26 @@ type HTTPXData struct {
 	Path          string      `json:"path" db:"path"`
 	Time          string      `json:"time" db:"time"`
 	A             StringArray `json:"a" db:"a"`
+	AAAA          StringArray `json:"aaaa,omitempty" db:"-"`
+	CNAME         StringArray `json:"cname,omitempty" db:"cname"`
 	Tech          StringArray `json:"tech" db:"tech"`
 	Words         int         `json:"words" db:"words"`
 	Lines         int         `json:"lines" db:"lines"`
 	StatusCode    int         `json:"status_code" db:"status_code"`
 	ContentLength int         `json:"content_length" db:"content_length"`
+	Hostname      string      `json:"hostname,omitempty" db:"hostname"`
+	RootDomain    string      `json:"root_domain,omitempty" db:"root_domain"`
+	Subdomain     string      `json:"subdomain,omitempty" db:"subdomain"`
+	Headers       Headers     `json:"header,omitempty" db:"headers"`
+	Body          string      `json:"body,omitempty" db:"-"`
+	BodyHash      string      `json:"body_hash,omitempty" db:"body_hash"`
+	Hash          Hashes      `json:"hash,omitempty" db:"-"`
+	Favicon       string      `json:"favicon,omitempty" db:"favicon"`
+	Jarm          string      `json:"jarm,omitempty" db:"jarm"`
+	JarmHash      string      `json:"jarm_hash,omitempty" db:"-"`
+	TLS           *TLSData    `json:"tls,omitempty" db:"-"`
+	Labels        StringArray `json:"labels,omitempty" db:"labels"`
+	Source        string      `json:"source,omitempty" db:"source"`
 	Program       string      `json:"program" db:"program"`
 	Platform      string      `json:"platform" db:"platform"`
 }
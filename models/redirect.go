@@ -0,0 +1,55 @@
+package models
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"errors"
+)
+
+// ChainHop is one response of a redirect chain as emitted by httpx
+// -include-chain. The raw request and response are not kept.
+type ChainHop struct {
+	RequestURL string `json:"request-url"`
+	StatusCode int    `json:"status_code"`
+	Location   string `json:"location,omitempty"`
+}
+
+// Chain is a redirect chain, stored as JSONB
+type Chain []ChainHop
+
+func (c Chain) Value() (driver.Value, error) {
+	if c == nil {
+		return nil, nil
+	}
+	return json.Marshal(c)
+}
+
+func (c *Chain) Scan(value interface{}) error {
+	if value == nil {
+		*c = nil
+		return nil
+	}
+	bytes, ok := value.([]byte)
+	if !ok {
+		return errors.New("failed to scan Chain")
+	}
+	return json.Unmarshal(bytes, c)
+}
+
+// RedirectSummary groups the records redirecting to one destination
+type RedirectSummary struct {
+	Destination string   `json:"destination"`
+	RootDomain  string   `json:"root_domain"`
+	External    bool     `json:"external"`
+	Hosts       []string `json:"hosts"`
+	Programs    []string `json:"programs"`
+	Records     int64    `json:"records"`
+}
+
+// SchemeRedirect is a host redirecting to itself on another scheme
+type SchemeRedirect struct {
+	Hostname string `json:"hostname"`
+	URL      string `json:"url"`
+	Target   string `json:"target"`
+	Program  string `json:"program"`
+}
@@ -0,0 +1,25 @@
+package models
+
+import "time"
+
+// HTTPRequest is a captured request/response pair, e.g. from a HAR file.
+// RecordID links it to the latest stored record of its host.
+type HTTPRequest struct {
+	ID              int64     `json:"id"`
+	RecordID        *int64    `json:"record_id,omitempty"`
+	Program         string    `json:"program"`
+	Platform        string    `json:"platform"`
+	Source          string    `json:"source"`
+	Method          string    `json:"method"`
+	URL             string    `json:"url"`
+	Hostname        string    `json:"hostname"`
+	Path            string    `json:"path"`
+	RequestHeaders  Headers   `json:"request_headers,omitempty"`
+	RequestBody     string    `json:"request_body,omitempty"`
+	StatusCode      int       `json:"status_code"`
+	ResponseHeaders Headers   `json:"response_headers,omitempty"`
+	ContentType     string    `json:"content_type"`
+	ResponseBody    string    `json:"response_body,omitempty"`
+	BodyHash        string    `json:"body_hash,omitempty"`
+	StartedAt       time.Time `json:"started_at"`
+}
@@ -0,0 +1,99 @@
+package models
+
+import "time"
+
+// HostSummary aggregates every stored record of a single host
+type HostSummary struct {
+	Host        string   `json:"host"`
+	Ports       []string `json:"ports"`
+	Schemes     []string `json:"schemes"`
+	StatusCodes []int32  `json:"status_codes"`
+	Tech        []string `json:"tech"`
+	A           []string `json:"a"`
+	Programs    []string `json:"programs"`
+	Sources     []string `json:"sources"`
+	Records     int64    `json:"records"`
+}
+
+// IPSummary aggregates the hosts resolving to a single IP address
+type IPSummary struct {
+	IP        string   `json:"ip"`
+	Hosts     []string `json:"hosts"`
+	HostCount int64    `json:"host_count"`
+	Programs  []string `json:"programs"`
+}
+
+// SubdomainCount is the number of records stored for one subdomain of a
+// registrable domain; an empty Subdomain is the apex itself
+type SubdomainCount struct {
+	Subdomain string `json:"subdomain"`
+	Records   int64  `json:"records"`
+}
+
+// TechSummary is the version spread of one technology within a program
+type TechSummary struct {
+	Name     string         `json:"name"`
+	Category string         `json:"category,omitempty"`
+	Program  string         `json:"program"`
+	Hosts    int64          `json:"hosts"`
+	Versions []VersionCount `json:"versions"`
+}
+
+// VersionCount is the number of distinct hosts running a technology version;
+// an empty Version means httpx did not report one
+type VersionCount struct {
+	Version string `json:"version"`
+	Hosts   int64  `json:"hosts"`
+}
+
+// TechInstance is a technology version seen on a host
+type TechInstance struct {
+	Host         string  `json:"host"`
+	Name         string  `json:"name"`
+	Version      string  `json:"version"`
+	VersionParts []int32 `json:"-"`
+	Program      string  `json:"program"`
+}
+
+// VulnMatch is a known CVE affecting a technology version seen on a host
+type VulnMatch struct {
+	Host       string  `json:"host"`
+	Technology string  `json:"technology"`
+	Version    string  `json:"version"`
+	CVE        string  `json:"cve"`
+	CVSS       float64 `json:"cvss"`
+	Confidence string  `json:"confidence"`
+	Program    string  `json:"program"`
+}
+
+// Response is the stored headers and decompressed body of a record
+type Response struct {
+	ID      int64
+	URL     string
+	Program string
+	Headers Headers
+	Body    []byte
+}
+
+// Wildcard is a parent domain whose subdomains all answer the same way,
+// typically because of wildcard DNS or a catch-all virtual host
+type Wildcard struct {
+	Program    string    `json:"program"`
+	Parent     string    `json:"parent"`
+	IPs        string    `json:"ips"`
+	StatusCode int       `json:"status_code"`
+	Hosts      int       `json:"hosts"`
+	DetectedAt time.Time `json:"detected_at"`
+}
+
+// Scan is a full scan of a program by one source, used to track which
+// assets are still live.
+type Scan struct {
+	ID        int64     `json:"id"`
+	Program   string    `json:"program"`
+	Source    string    `json:"source"`
+	StartedAt time.Time `json:"started_at"`
+	Seen      int64     `json:"seen"`
+	Missing   int64     `json:"missing"`
+	Gone      int64     `json:"gone"`
+}
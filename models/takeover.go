@@ -0,0 +1,19 @@
+package models
+
+import "time"
+
+// TakeoverCandidate is a host whose CNAME points at an unclaimed resource of
+// a third-party service
+type TakeoverCandidate struct {
+	ID         int64     `json:"id,omitempty"`
+	Program    string    `json:"program"`
+	Hostname   string    `json:"hostname"`
+	URL        string    `json:"url"`
+	Service    string    `json:"service"`
+	CNAME      string    `json:"cname"`
+	Evidence   string    `json:"evidence"`
+	Confidence string    `json:"confidence"`
+	State      string    `json:"state,omitempty"`
+	FirstSeen  time.Time `json:"first_seen"`
+	LastSeen   time.Time `json:"last_seen"`
+}
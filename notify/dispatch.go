@@ -0,0 +1,74 @@
+package notify
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/itsmeashim/rdb/config"
+	"github.com/itsmeashim/rdb/models"
+)
+
+// State keeps the delivery state of each webhook between runs: when it was
+// last notified and the changes waiting for its next digest.
+type State interface {
+	LastNotified(ctx context.Context, webhook string) (time.Time, error)
+	MarkNotified(ctx context.Context, webhook string) error
+	Queue(ctx context.Context, webhook string, changes []models.Change) error
+	TakeQueued(ctx context.Context, webhook string) ([]models.Change, error)
+}
+
+// Dispatch sends one digest per webhook for the changes matched by the
+// notification rules. Changes for rate limited or failing webhooks are
+// queued in state for the next run, unless the webhook rejected them.
+// Failures do not stop the other webhooks and are returned together.
+func Dispatch(ctx context.Context, cfg config.NotifyConfig, changes []models.Change, state State) error {
+	routed := Route(cfg, changes)
+	interval := time.Duration(cfg.MinIntervalSeconds) * time.Second
+
+	var errs []error
+	for _, hook := range cfg.Webhooks {
+		pending := routed[hook.Name]
+
+		last, err := state.LastNotified(ctx, hook.Name)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("failed to read notification state: %w", err))
+			continue
+		}
+		if interval > 0 && time.Since(last) < interval {
+			if len(pending) > 0 {
+				if err := state.Queue(ctx, hook.Name, pending); err != nil {
+					errs = append(errs, fmt.Errorf("failed to queue notifications: %w", err))
+				}
+			}
+			continue
+		}
+
+		queued, err := state.TakeQueued(ctx, hook.Name)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("failed to read queued notifications: %w", err))
+		}
+		pending = append(queued, pending...)
+		if len(pending) == 0 {
+			continue
+		}
+
+		if err := Send(ctx, hook, pending, cfg.MaxEvents); err != nil {
+			errs = append(errs, fmt.Errorf("failed to notify %s: %w", hook.Name, err))
+			if errors.Is(err, ErrRejected) {
+				// Queuing a rejected digest would only fail again on every run.
+				errs = append(errs, fmt.Errorf("dropped %d change(s) for %s", len(pending), hook.Name))
+				continue
+			}
+			if err := state.Queue(ctx, hook.Name, pending); err != nil {
+				errs = append(errs, fmt.Errorf("failed to queue notifications: %w", err))
+			}
+			continue
+		}
+		if err := state.MarkNotified(ctx, hook.Name); err != nil {
+			errs = append(errs, fmt.Errorf("failed to save notification state: %w", err))
+		}
+	}
+	return errors.Join(errs...)
+}
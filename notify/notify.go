@@ -0,0 +1,165 @@
+package notify
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/itsmeashim/rdb/config"
+	"github.com/itsmeashim/rdb/models"
+)
+
+// Message length limits of the chat webhook types. Slack cuts text beyond
+// its limit itself, Discord and Telegram reject the message.
+const (
+	discordLimit  = 2000
+	telegramLimit = 4096
+	slackLimit    = 4000
+)
+
+// ErrRejected marks a send the webhook refused outright (a 4xx response
+// other than 408 or 429). Retrying the same payload would fail again.
+var ErrRejected = errors.New("payload rejected")
+
+var client = &http.Client{Timeout: 15 * time.Second}
+
+// Route applies the notification rules to changes and returns the changes
+// to send per webhook name. A change matched by several rules for the same
+// webhook is only included once.
+func Route(cfg config.NotifyConfig, changes []models.Change) map[string][]models.Change {
+	routed := map[string][]models.Change{}
+	for _, c := range changes {
+		seen := map[string]bool{}
+		for _, rule := range cfg.Rules {
+			if !matches(rule, c) {
+				continue
+			}
+			for _, hook := range rule.Webhooks {
+				if !seen[hook] {
+					seen[hook] = true
+					routed[hook] = append(routed[hook], c)
+				}
+			}
+		}
+	}
+	return routed
+}
+
+func matches(rule config.NotifyRule, c models.Change) bool {
+	if len(rule.Events) > 0 && !slices.Contains(rule.Events, c.Type) {
+		return false
+	}
+	if len(rule.Programs) > 0 && !slices.Contains(rule.Programs, c.Program) {
+		return false
+	}
+	if c.Type == models.ChangeStatus {
+		from, _ := strconv.Atoi(c.From)
+		to, _ := strconv.Atoi(c.To)
+		if len(rule.FromStatus) > 0 && !slices.Contains(rule.FromStatus, from) {
+			return false
+		}
+		if len(rule.ToStatus) > 0 && !slices.Contains(rule.ToStatus, to) {
+			return false
+		}
+	}
+	return true
+}
+
+// Digest renders changes as one plain-text message, listing at most
+// maxEvents of them (0 = all).
+func Digest(changes []models.Change, maxEvents int) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "rdb: %d change(s)\n", len(changes))
+
+	shown := changes
+	if maxEvents > 0 && len(shown) > maxEvents {
+		shown = shown[:maxEvents]
+	}
+	for _, c := range shown {
+		switch c.Type {
+		case models.ChangeNewHost:
+			fmt.Fprintf(&b, "[new host] %s (%s)\n", c.Host, c.Program)
+		case models.ChangeNewURL:
+			fmt.Fprintf(&b, "[new url] %s (%s)\n", c.URL, c.Program)
+		case models.ChangeStatus:
+			fmt.Fprintf(&b, "[status] %s %s -> %s (%s)\n", c.URL, c.From, c.To, c.Program)
+		case models.ChangeNewTech:
+			fmt.Fprintf(&b, "[new tech] %s %s (%s)\n", c.Host, c.To, c.Program)
+		case models.ChangeTitle:
+			fmt.Fprintf(&b, "[title] %s %q -> %q (%s)\n", c.URL, c.From, c.To, c.Program)
+		default:
+			fmt.Fprintf(&b, "[%s] %s (%s)\n", c.Type, c.URL, c.Program)
+		}
+	}
+	if len(shown) < len(changes) {
+		fmt.Fprintf(&b, "... and %d more\n", len(changes)-len(shown))
+	}
+	return b.String()
+}
+
+// truncate shortens text to at most max bytes, cutting on a rune boundary
+// and marking the cut.
+func truncate(text string, max int) string {
+	const more = "\n..."
+	if len(text) <= max {
+		return text
+	}
+	cut := max - len(more)
+	for cut > 0 && !utf8.RuneStart(text[cut]) {
+		cut--
+	}
+	return text[:cut] + more
+}
+
+// Send posts a digest of changes to hook in the payload format of its type.
+func Send(ctx context.Context, hook config.Webhook, changes []models.Change, maxEvents int) error {
+	text := Digest(changes, maxEvents)
+
+	var payload interface{}
+	switch hook.Type {
+	case "", "webhook":
+		payload = map[string]interface{}{"text": text, "changes": changes}
+	case "slack":
+		payload = map[string]string{"text": truncate(text, slackLimit)}
+	case "discord":
+		payload = map[string]string{"content": truncate(text, discordLimit)}
+	case "telegram":
+		payload = map[string]string{"chat_id": hook.ChatID, "text": truncate(text, telegramLimit)}
+	default:
+		return fmt.Errorf("unknown webhook type %q", hook.Type)
+	}
+
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, hook.URL, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	switch {
+	case resp.StatusCode >= 400 && resp.StatusCode < 500 &&
+		resp.StatusCode != http.StatusRequestTimeout && resp.StatusCode != http.StatusTooManyRequests:
+		return fmt.Errorf("webhook %s returned %s: %w", hook.Name, resp.Status, ErrRejected)
+	case resp.StatusCode < 200 || resp.StatusCode >= 300:
+		return fmt.Errorf("webhook %s returned %s", hook.Name, resp.Status)
+	}
+	return nil
+}
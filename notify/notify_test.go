@@ -0,0 +1,358 @@
+package notify
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+	"unicode/utf8"
+
+	"github.com/itsmeashim/rdb/config"
+	"github.com/itsmeashim/rdb/models"
+)
+
+// receiver is a local HTTP endpoint recording the JSON bodies posted to it.
+type receiver struct {
+	*httptest.Server
+	mu     sync.Mutex
+	bodies []map[string]interface{}
+}
+
+func newReceiver(t *testing.T) *receiver {
+	t.Helper()
+	r := &receiver{}
+	r.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		if req.Method != http.MethodPost {
+			t.Errorf("method = %s, want POST", req.Method)
+		}
+		if ct := req.Header.Get("Content-Type"); ct != "application/json" {
+			t.Errorf("Content-Type = %q, want application/json", ct)
+		}
+		data, err := io.ReadAll(req.Body)
+		if err != nil {
+			t.Errorf("read body: %v", err)
+		}
+		var body map[string]interface{}
+		if err := json.Unmarshal(data, &body); err != nil {
+			t.Errorf("body is not a JSON object: %v", err)
+		}
+		r.mu.Lock()
+		r.bodies = append(r.bodies, body)
+		r.mu.Unlock()
+	}))
+	t.Cleanup(r.Close)
+	return r
+}
+
+func (r *receiver) received() []map[string]interface{} {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return append([]map[string]interface{}(nil), r.bodies...)
+}
+
+// memState is an in-memory State.
+type memState struct {
+	last   map[string]time.Time
+	queued map[string][]models.Change
+}
+
+func newMemState() *memState {
+	return &memState{last: map[string]time.Time{}, queued: map[string][]models.Change{}}
+}
+
+func (s *memState) LastNotified(_ context.Context, webhook string) (time.Time, error) {
+	return s.last[webhook], nil
+}
+
+func (s *memState) MarkNotified(_ context.Context, webhook string) error {
+	s.last[webhook] = time.Now()
+	return nil
+}
+
+func (s *memState) Queue(_ context.Context, webhook string, changes []models.Change) error {
+	s.queued[webhook] = append(s.queued[webhook], changes...)
+	return nil
+}
+
+func (s *memState) TakeQueued(_ context.Context, webhook string) ([]models.Change, error) {
+	changes := s.queued[webhook]
+	delete(s.queued, webhook)
+	return changes, nil
+}
+
+var sample = []models.Change{
+	{Type: models.ChangeNewHost, Program: "acme", Host: "new.acme.com", URL: "https://new.acme.com"},
+	{Type: models.ChangeStatus, Program: "acme", Host: "admin.acme.com", URL: "https://admin.acme.com", From: "403", To: "200"},
+	{Type: models.ChangeNewTech, Program: "other", Host: "shop.other.com", URL: "https://shop.other.com", To: "WordPress"},
+}
+
+func TestSendPayloads(t *testing.T) {
+	tests := []struct {
+		typ  string
+		keys []string
+	}{
+		{"webhook", []string{"changes", "text"}},
+		{"slack", []string{"text"}},
+		{"discord", []string{"content"}},
+		{"telegram", []string{"chat_id", "text"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.typ, func(t *testing.T) {
+			r := newReceiver(t)
+			hook := config.Webhook{Name: tt.typ, Type: tt.typ, URL: r.URL, ChatID: "42"}
+			if err := Send(context.Background(), hook, sample, 0); err != nil {
+				t.Fatalf("Send: %v", err)
+			}
+
+			got := r.received()
+			if len(got) != 1 {
+				t.Fatalf("received %d requests, want 1", len(got))
+			}
+			body := got[0]
+			if len(body) != len(tt.keys) {
+				t.Errorf("payload keys = %v, want %v", body, tt.keys)
+			}
+			for _, k := range tt.keys {
+				if _, ok := body[k]; !ok {
+					t.Errorf("payload has no %q: %v", k, body)
+				}
+			}
+
+			text, _ := body["text"].(string)
+			if tt.typ == "discord" {
+				text, _ = body["content"].(string)
+			}
+			if !strings.Contains(text, "[status] https://admin.acme.com 403 -> 200 (acme)") {
+				t.Errorf("digest misses the status change:\n%s", text)
+			}
+			if tt.typ == "telegram" && body["chat_id"] != "42" {
+				t.Errorf("chat_id = %v, want 42", body["chat_id"])
+			}
+			if tt.typ == "webhook" {
+				if changes, _ := body["changes"].([]interface{}); len(changes) != len(sample) {
+					t.Errorf("changes = %v, want %d entries", body["changes"], len(sample))
+				}
+			}
+		})
+	}
+}
+
+func TestSendUnknownType(t *testing.T) {
+	hook := config.Webhook{Name: "x", Type: "pager", URL: "http://127.0.0.1:0"}
+	if err := Send(context.Background(), hook, sample, 0); err == nil {
+		t.Fatal("Send with an unknown type succeeded")
+	}
+}
+
+func TestSendErrorStatus(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, "nope", http.StatusTooManyRequests)
+	}))
+	defer srv.Close()
+
+	hook := config.Webhook{Name: "slack", Type: "slack", URL: srv.URL}
+	err := Send(context.Background(), hook, sample, 0)
+	if err == nil {
+		t.Fatal("Send succeeded on a 429 response")
+	}
+	if errors.Is(err, ErrRejected) {
+		t.Errorf("429 response reported as rejected: %v", err)
+	}
+}
+
+func TestChatTruncation(t *testing.T) {
+	var changes []models.Change
+	for i := 0; i < 500; i++ {
+		changes = append(changes, models.Change{Type: models.ChangeTitle, Program: "acme",
+			URL: "https://acme.com", From: "Старая страница", To: "Новая страница"})
+	}
+	tests := []struct {
+		typ   string
+		key   string
+		limit int
+	}{
+		{"discord", "content", discordLimit},
+		{"slack", "text", slackLimit},
+		{"telegram", "text", telegramLimit},
+	}
+	for _, tt := range tests {
+		t.Run(tt.typ, func(t *testing.T) {
+			r := newReceiver(t)
+			hook := config.Webhook{Name: tt.typ, Type: tt.typ, URL: r.URL, ChatID: "42"}
+			if err := Send(context.Background(), hook, changes, 0); err != nil {
+				t.Fatalf("Send: %v", err)
+			}
+
+			content := r.received()[0][tt.key].(string)
+			if len(content) > tt.limit {
+				t.Errorf("%s is %d bytes, want at most %d", tt.key, len(content), tt.limit)
+			}
+			if !utf8.ValidString(content) {
+				t.Errorf("%s is not valid UTF-8", tt.key)
+			}
+			if !strings.HasSuffix(content, "\n...") {
+				t.Errorf("%s does not end with the truncation mark: %q", tt.key, content[len(content)-20:])
+			}
+		})
+	}
+}
+
+func TestTruncate(t *testing.T) {
+	if got := truncate("short", 10); got != "short" {
+		t.Errorf("truncate(short) = %q", got)
+	}
+	// "é" is two bytes: a cut at byte 5 would split the second one.
+	got := truncate("éééééééé", 9)
+	if !utf8.ValidString(got) || len(got) > 9 {
+		t.Errorf("truncate = %q (%d bytes), want valid UTF-8 of at most 9 bytes", got, len(got))
+	}
+	if got != "éé\n..." {
+		t.Errorf("truncate = %q, want %q", got, "éé\n...")
+	}
+}
+
+func TestDispatchBatchesOneDigestPerRun(t *testing.T) {
+	slack := newReceiver(t)
+	discord := newReceiver(t)
+	cfg := config.NotifyConfig{
+		Webhooks: []config.Webhook{
+			{Name: "team", Type: "slack", URL: slack.URL},
+			{Name: "me", Type: "discord", URL: discord.URL},
+		},
+		Rules: []config.NotifyRule{
+			{Name: "all", Webhooks: []string{"team"}},
+			{Name: "acme", Programs: []string{"acme"}, Webhooks: []string{"team", "me"}},
+		},
+	}
+
+	if err := Dispatch(context.Background(), cfg, sample, newMemState()); err != nil {
+		t.Fatalf("Dispatch: %v", err)
+	}
+
+	got := slack.received()
+	if len(got) != 1 {
+		t.Fatalf("slack received %d requests, want one digest", len(got))
+	}
+	// Matched by both rules, each change is still listed once.
+	if text := got[0]["text"].(string); !strings.HasPrefix(text, "rdb: 3 change(s)\n") {
+		t.Errorf("slack digest:\n%s", text)
+	}
+
+	got = discord.received()
+	if len(got) != 1 {
+		t.Fatalf("discord received %d requests, want one digest", len(got))
+	}
+	content := got[0]["content"].(string)
+	if !strings.HasPrefix(content, "rdb: 2 change(s)\n") || strings.Contains(content, "other.com") {
+		t.Errorf("discord digest should only hold the acme changes:\n%s", content)
+	}
+}
+
+func TestDispatchMaxEvents(t *testing.T) {
+	r := newReceiver(t)
+	cfg := config.NotifyConfig{
+		Webhooks:  []config.Webhook{{Name: "team", Type: "slack", URL: r.URL}},
+		Rules:     []config.NotifyRule{{Name: "all", Webhooks: []string{"team"}}},
+		MaxEvents: 1,
+	}
+	if err := Dispatch(context.Background(), cfg, sample, newMemState()); err != nil {
+		t.Fatalf("Dispatch: %v", err)
+	}
+	text := r.received()[0]["text"].(string)
+	if !strings.Contains(text, "... and 2 more") {
+		t.Errorf("digest does not cap the listed changes:\n%s", text)
+	}
+}
+
+func TestDispatchRateLimit(t *testing.T) {
+	r := newReceiver(t)
+	cfg := config.NotifyConfig{
+		Webhooks:           []config.Webhook{{Name: "team", Type: "webhook", URL: r.URL}},
+		Rules:              []config.NotifyRule{{Name: "all", Webhooks: []string{"team"}}},
+		MinIntervalSeconds: 3600,
+	}
+	state := newMemState()
+	ctx := context.Background()
+
+	if err := Dispatch(ctx, cfg, sample[:1], state); err != nil {
+		t.Fatalf("first Dispatch: %v", err)
+	}
+	if n := len(r.received()); n != 1 {
+		t.Fatalf("first run sent %d requests, want 1", n)
+	}
+
+	// Within the interval: held back, nothing sent.
+	if err := Dispatch(ctx, cfg, sample[1:], state); err != nil {
+		t.Fatalf("second Dispatch: %v", err)
+	}
+	if n := len(r.received()); n != 1 {
+		t.Fatalf("rate limited run sent a request (%d total)", n)
+	}
+	if n := len(state.queued["team"]); n != 2 {
+		t.Fatalf("queued %d changes, want 2", n)
+	}
+
+	// Once the interval passed, the queued changes go out in the next digest.
+	state.last["team"] = time.Now().Add(-2 * time.Hour)
+	if err := Dispatch(ctx, cfg, nil, state); err != nil {
+		t.Fatalf("third Dispatch: %v", err)
+	}
+	got := r.received()
+	if len(got) != 2 {
+		t.Fatalf("sent %d requests in total, want 2", len(got))
+	}
+	if changes, _ := got[1]["changes"].([]interface{}); len(changes) != 2 {
+		t.Errorf("digest after the interval holds %v, want the 2 queued changes", got[1]["changes"])
+	}
+	if len(state.queued["team"]) != 0 {
+		t.Error("queue not emptied after sending")
+	}
+}
+
+func TestDispatchQueuesOnFailure(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusBadGateway)
+	}))
+	defer srv.Close()
+
+	cfg := config.NotifyConfig{
+		Webhooks: []config.Webhook{{Name: "team", Type: "slack", URL: srv.URL}},
+		Rules:    []config.NotifyRule{{Name: "all", Webhooks: []string{"team"}}},
+	}
+	state := newMemState()
+	if err := Dispatch(context.Background(), cfg, sample, state); err == nil {
+		t.Fatal("Dispatch reported no error for a failing webhook")
+	}
+	if n := len(state.queued["team"]); n != len(sample) {
+		t.Errorf("queued %d changes after a failure, want %d", n, len(sample))
+	}
+	if !state.last["team"].IsZero() {
+		t.Error("failed delivery marked the webhook as notified")
+	}
+}
+
+func TestDispatchDropsRejected(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, "invalid_payload", http.StatusBadRequest)
+	}))
+	defer srv.Close()
+
+	cfg := config.NotifyConfig{
+		Webhooks: []config.Webhook{{Name: "team", Type: "slack", URL: srv.URL}},
+		Rules:    []config.NotifyRule{{Name: "all", Webhooks: []string{"team"}}},
+	}
+	state := newMemState()
+	err := Dispatch(context.Background(), cfg, sample, state)
+	if !errors.Is(err, ErrRejected) {
+		t.Fatalf("Dispatch error = %v, want ErrRejected", err)
+	}
+	if n := len(state.queued["team"]); n != 0 {
+		t.Errorf("queued %d rejected changes, want none", n)
+	}
+}
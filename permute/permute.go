@@ -0,0 +1,172 @@
+package permute
+
+import (
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/itsmeashim/rdb/domain"
+)
+
+// EnvWords are environment and role words combined with every host.
+var EnvWords = []string{
+	"dev", "develop", "staging", "stage", "stg", "test", "qa", "uat", "preprod", "prod",
+	"internal", "int", "beta", "demo", "sandbox", "old", "new", "v1", "v2", "api", "admin",
+}
+
+var separators = []string{"-", ""}
+
+var trailingNumber = regexp.MustCompile(`^(.*?)(\d+)$`)
+
+// host is a known hostname split into its leftmost label and the rest.
+type host struct {
+	label, parent string
+}
+
+// LearnedWords returns the most frequent labels, and dash-separated parts
+// of labels, of the subdomains of hosts, at most limit of them.
+func LearnedWords(hosts []string, limit int) []string {
+	counts := map[string]int{}
+	for _, h := range hosts {
+		_, sub := domain.Split(h)
+		if sub == "" {
+			continue
+		}
+		seen := map[string]bool{}
+		for _, label := range strings.Split(sub, ".") {
+			for _, w := range append(strings.Split(label, "-"), label) {
+				w = trailingNumber.ReplaceAllString(w, "$1")
+				if len(w) > 1 && !seen[w] {
+					seen[w] = true
+					counts[w]++
+				}
+			}
+		}
+	}
+
+	words := make([]string, 0, len(counts))
+	for w := range counts {
+		words = append(words, w)
+	}
+	sort.Slice(words, func(i, j int) bool {
+		if counts[words[i]] != counts[words[j]] {
+			return counts[words[i]] > counts[words[j]]
+		}
+		return words[i] < words[j]
+	})
+	if limit > 0 && len(words) > limit {
+		words = words[:limit]
+	}
+	return words
+}
+
+// Generate calls emit with altdns/dnsgen-style permutations of hosts built
+// from words, skipping hosts in exists and duplicates, until emit has been
+// called max times (0 = no limit). Cheaper, likelier strategies run first
+// over all hosts: number changes, then words joined to the leftmost label,
+// then words replacing it, then words prepended as a new label.
+func Generate(hosts, words []string, exists map[string]bool, max int, emit func(string)) int {
+	var parsed []host
+	for _, h := range hosts {
+		root, sub := domain.Split(h)
+		if sub == "" {
+			continue
+		}
+		label, rest, _ := strings.Cut(sub, ".")
+		parent := root
+		if rest != "" {
+			parent = rest + "." + root
+		}
+		parsed = append(parsed, host{label: label, parent: parent})
+	}
+
+	seen := map[string]bool{}
+	count := 0
+	add := func(label, parent string) bool {
+		candidate := label + "." + parent
+		if !validLabel(label) || seen[candidate] || exists[candidate] {
+			return true
+		}
+		seen[candidate] = true
+		emit(candidate)
+		count++
+		return max <= 0 || count < max
+	}
+
+	strategies := []func(h host) bool{
+		// api -> api1, api-2, api01; api2 -> api1, api3
+		func(h host) bool {
+			base, num := h.label, 0
+			if m := trailingNumber.FindStringSubmatch(h.label); m != nil && m[1] != "" {
+				base = strings.TrimSuffix(m[1], "-")
+				num, _ = strconv.Atoi(m[2])
+			}
+			for _, n := range []int{num - 1, num + 1, 1, 2, 3} {
+				if n < 0 {
+					continue
+				}
+				for _, sep := range separators {
+					if !add(base+sep+strconv.Itoa(n), h.parent) {
+						return false
+					}
+				}
+			}
+			return add(base+"01", h.parent)
+		},
+		// api -> api-dev, dev-api, apidev, devapi
+		func(h host) bool {
+			for _, w := range words {
+				if strings.Contains(h.label, w) {
+					continue
+				}
+				for _, sep := range separators {
+					if !add(h.label+sep+w, h.parent) || !add(w+sep+h.label, h.parent) {
+						return false
+					}
+				}
+			}
+			return true
+		},
+		// api.example.com -> dev.example.com
+		func(h host) bool {
+			for _, w := range words {
+				if !add(w, h.parent) {
+					return false
+				}
+			}
+			return true
+		},
+		// api.example.com -> dev.api.example.com
+		func(h host) bool {
+			for _, w := range words {
+				if !add(w, h.label+"."+h.parent) {
+					return false
+				}
+			}
+			return true
+		},
+	}
+
+	for _, strategy := range strategies {
+		for _, h := range parsed {
+			if !strategy(h) {
+				return count
+			}
+		}
+	}
+	return count
+}
+
+// validLabel reports whether label is a valid DNS label.
+func validLabel(label string) bool {
+	if label == "" || len(label) > 63 || label[0] == '-' || label[len(label)-1] == '-' {
+		return false
+	}
+	for _, r := range label {
+		if !(r >= 'a' && r <= 'z' || r >= '0' && r <= '9' || r == '-') {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,49 @@
+// Package rulefile loads the YAML files that extend a built-in rule set,
+// shared by the labeling and extraction rules.
+package rulefile
+
+import (
+	"fmt"
+	"os"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Load returns a copy of builtin extended with the rules listed under
+// "rules" in the YAML file at path; an empty path returns builtin alone.
+// Each custom rule is checked with validate, then replaces the built-in
+// rule it shares a name with or is appended.
+func Load[R any](path string, builtin []R, name func(R) string, validate func(R) error) ([]R, error) {
+	rules := make([]R, len(builtin))
+	copy(rules, builtin)
+	if path == "" {
+		return rules, nil
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var f struct {
+		Rules []R `yaml:"rules"`
+	}
+	if err := yaml.Unmarshal(data, &f); err != nil {
+		return nil, fmt.Errorf("failed to parse rules file %s: %w", path, err)
+	}
+
+	for _, custom := range f.Rules {
+		if err := validate(custom); err != nil {
+			return nil, fmt.Errorf("rule %q: %w", name(custom), err)
+		}
+		replaced := false
+		for i := range rules {
+			if name(rules[i]) == name(custom) {
+				rules[i], replaced = custom, true
+			}
+		}
+		if !replaced {
+			rules = append(rules, custom)
+		}
+	}
+	return rules, nil
+}
@@ -0,0 +1,62 @@
+package scope
+
+import (
+	"net"
+	"regexp"
+	"strings"
+
+	"github.com/itsmeashim/rdb/config"
+)
+
+// Match reports whether host matches pattern: an exact hostname, a
+// "*.example.com" wildcard matching any subdomain of example.com, an IP
+// address or a CIDR range.
+func Match(pattern, host string) bool {
+	pattern = strings.ToLower(strings.TrimSpace(pattern))
+	host = strings.TrimSuffix(strings.ToLower(host), ".")
+	if pattern == "" || host == "" {
+		return false
+	}
+
+	if _, network, err := net.ParseCIDR(pattern); err == nil {
+		ip := net.ParseIP(host)
+		return ip != nil && network.Contains(ip)
+	}
+	if suffix, ok := strings.CutPrefix(pattern, "*."); ok {
+		return strings.HasSuffix(host, "."+suffix)
+	}
+	return host == pattern
+}
+
+// Allows reports whether host is in scope: it matches one of s.In, when s.In
+// is not empty, and none of s.Out.
+func Allows(s config.Scope, host string) bool {
+	for _, p := range s.Out {
+		if Match(p, host) {
+			return false
+		}
+	}
+	if len(s.In) == 0 {
+		return true
+	}
+	for _, p := range s.In {
+		if Match(p, host) {
+			return true
+		}
+	}
+	return false
+}
+
+// Regex returns a regular expression matching the hosts pattern matches,
+// for tools such as Burp that take host regexes. CIDR ranges cannot be
+// expressed and return "".
+func Regex(pattern string) string {
+	pattern = strings.ToLower(strings.TrimSpace(pattern))
+	if _, _, err := net.ParseCIDR(pattern); err == nil || pattern == "" {
+		return ""
+	}
+	if suffix, ok := strings.CutPrefix(pattern, "*."); ok {
+		return `^.+\.` + regexp.QuoteMeta(suffix) + `$`
+	}
+	return "^" + regexp.QuoteMeta(pattern) + "$"
+}
@@ -0,0 +1,48 @@
+package takeover
+
+// builtin is a snapshot of the vulnerable services from can-i-take-over-xyz.
+// Run 'rdb takeover update' to fetch the current list.
+var builtin = []Fingerprint{
+	{Service: "Agile CRM", CNAME: []string{"agilecrm.com"}, Fingerprint: "Sorry, this page is no longer available.", Status: "Vulnerable", Vulnerable: true},
+	{Service: "Airee.ru", CNAME: []string{"airee.ru"}, Fingerprint: "Ошибка 402. Сервис Айри.рф не оплачен", Status: "Vulnerable", Vulnerable: true},
+	{Service: "Anima", CNAME: []string{"animaapp.io"}, Fingerprint: "The page you were looking for does not exist", Status: "Vulnerable", Vulnerable: true},
+	{Service: "AWS/Elastic Beanstalk", CNAME: []string{"elasticbeanstalk.com"}, Fingerprint: "NXDOMAIN", NXDomain: true, Status: "Vulnerable", Vulnerable: true},
+	{Service: "AWS/S3", CNAME: []string{"amazonaws.com"}, Fingerprint: "The specified bucket does not exist", Status: "Vulnerable", Vulnerable: true},
+	{Service: "Bitbucket", CNAME: []string{"bitbucket.io"}, Fingerprint: "Repository not found", Status: "Vulnerable", Vulnerable: true},
+	{Service: "Campaign Monitor", CNAME: []string{"createsend.com"}, Fingerprint: "Trying to access your account?", Status: "Vulnerable", Vulnerable: true},
+	{Service: "Canny", CNAME: []string{"cname.canny.io"}, Fingerprint: "Company Not Found", Status: "Vulnerable", Vulnerable: true},
+	{Service: "Digital Ocean", CNAME: []string{"digitalocean.com"}, Fingerprint: "Domain uses DO name servers with no records in DO.", Status: "Vulnerable", Vulnerable: true},
+	{Service: "Discourse", CNAME: []string{"trydiscourse.com"}, Fingerprint: "NXDOMAIN", NXDomain: true, Status: "Vulnerable", Vulnerable: true},
+	{Service: "Gemfury", CNAME: []string{"furyns.com"}, Fingerprint: "404: This page could not be found.", Status: "Vulnerable", Vulnerable: true},
+	{Service: "Ghost", CNAME: []string{"ghost.io"}, Fingerprint: "The thing you were looking for is no longer here, or never was", Status: "Vulnerable", Vulnerable: true},
+	{Service: "GitHub Pages", CNAME: []string{"github.io"}, Fingerprint: "There isn't a GitHub Pages site here.", Status: "Edge case", Vulnerable: true},
+	{Service: "HatenaBlog", CNAME: []string{"hatenablog.com"}, Fingerprint: "404 Blog is not found", Status: "Vulnerable", Vulnerable: true},
+	{Service: "Help Juice", CNAME: []string{"helpjuice.com"}, Fingerprint: "We could not find what you're looking for.", Status: "Vulnerable", Vulnerable: true},
+	{Service: "Help Scout", CNAME: []string{"helpscoutdocs.com"}, Fingerprint: "No settings were found for this company:", Status: "Vulnerable", Vulnerable: true},
+	{Service: "Heroku", CNAME: []string{"herokuapp.com", "herokudns.com"}, Fingerprint: "No such app", Status: "Edge case", Vulnerable: true},
+	{Service: "JetBrains", CNAME: []string{"myjetbrains.com"}, Fingerprint: "is not a registered InCloud YouTrack", Status: "Vulnerable", Vulnerable: true},
+	{Service: "Kinsta", CNAME: []string{"kinsta.cloud"}, Fingerprint: "No Site For Domain", Status: "Vulnerable", Vulnerable: true},
+	{Service: "LaunchRock", CNAME: []string{"launchrock.com"}, Fingerprint: "It looks like you may have taken a wrong turn somewhere.", Status: "Vulnerable", Vulnerable: true},
+	{Service: "Microsoft Azure", CNAME: []string{
+		"cloudapp.net", "cloudapp.azure.com", "azurewebsites.net", "blob.core.windows.net", "azure-api.net",
+		"azurehdinsight.net", "azureedge.net", "azurecontainer.io", "database.windows.net", "azuredatalakestore.net",
+		"search.windows.net", "azurecr.io", "redis.cache.windows.net", "servicebus.windows.net", "visualstudio.com",
+		"trafficmanager.net",
+	}, Fingerprint: "NXDOMAIN", NXDomain: true, Status: "Vulnerable", Vulnerable: true},
+	{Service: "Netlify", CNAME: []string{"netlify.app", "netlify.com"}, Fingerprint: "Not Found - Request ID:", Status: "Edge case", Vulnerable: true},
+	{Service: "Ngrok", CNAME: []string{"ngrok.io"}, Fingerprint: `Tunnel .*\.ngrok\.io not found`, Status: "Vulnerable", Vulnerable: true},
+	{Service: "Pantheon", CNAME: []string{"pantheonsite.io"}, Fingerprint: "404 error unknown site!", Status: "Vulnerable", Vulnerable: true},
+	{Service: "Pingdom", CNAME: []string{"stats.pingdom.com"}, Fingerprint: "Sorry, couldn't find the status page", Status: "Vulnerable", Vulnerable: true},
+	{Service: "Readme.io", CNAME: []string{"readme.io"}, Fingerprint: "Project doesnt exist... yet!", Status: "Vulnerable", Vulnerable: true},
+	{Service: "Shopify", CNAME: []string{"myshopify.com"}, Fingerprint: "Sorry, this shop is currently unavailable.", Status: "Edge case", Vulnerable: true},
+	{Service: "Short.io", CNAME: []string{"short.io"}, Fingerprint: "Link does not exist", Status: "Vulnerable", Vulnerable: true},
+	{Service: "SmartJobBoard", CNAME: []string{"smartjobboard.com"}, Fingerprint: "This job board website is either expired or its domain name is invalid.", Status: "Vulnerable", Vulnerable: true},
+	{Service: "Strikingly", CNAME: []string{"s.strikinglydns.com"}, Fingerprint: "PAGE NOT FOUND.", Status: "Vulnerable", Vulnerable: true},
+	{Service: "Surge.sh", CNAME: []string{"surge.sh"}, Fingerprint: "project not found", Status: "Vulnerable", Vulnerable: true},
+	{Service: "Tumblr", CNAME: []string{"domains.tumblr.com"}, Fingerprint: "Whatever you were looking for doesn't currently exist at this address.", Status: "Edge case", Vulnerable: true},
+	{Service: "Uberflip", CNAME: []string{"read.uberflip.com"}, Fingerprint: "The URL you've accessed does not provide a hub.", Status: "Vulnerable", Vulnerable: true},
+	{Service: "Uptimerobot", CNAME: []string{"stats.uptimerobot.com"}, Fingerprint: "page not found", Status: "Vulnerable", Vulnerable: true},
+	{Service: "Webflow", CNAME: []string{"proxy.webflow.com", "proxy-ssl.webflow.com"}, Fingerprint: "The page you are looking for doesn't exist or has been moved.", Status: "Edge case", Vulnerable: true},
+	{Service: "Wordpress", CNAME: []string{"wordpress.com"}, Fingerprint: `Do you want to register .*\.wordpress\.com\?`, Status: "Vulnerable", Vulnerable: true},
+	{Service: "Worksites", CNAME: []string{"worksites.net"}, Fingerprint: "Hello! Sorry, but the website you&rsquo;re looking for doesn&rsquo;t exist.", Status: "Vulnerable", Vulnerable: true},
+}
@@ -0,0 +1,123 @@
+package takeover
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"regexp"
+	"strings"
+
+	"github.com/itsmeashim/rdb/models"
+)
+
+// SourceURL is the upstream fingerprint list 'rdb takeover update' fetches.
+const SourceURL = "https://raw.githubusercontent.com/EdOverflow/can-i-take-over-xyz/master/fingerprints.json"
+
+// Fingerprint describes a service whose dangling CNAMEs can be claimed, in
+// the can-i-take-over-xyz fingerprints.json format. Fingerprint is a regular
+// expression (or literal text) found in the body or title of an unclaimed
+// resource; NXDomain services are only vulnerable when the CNAME target
+// does not resolve.
+type Fingerprint struct {
+	Service     string   `json:"service"`
+	CNAME       []string `json:"cname"`
+	Fingerprint string   `json:"fingerprint"`
+	HTTPStatus  *int     `json:"http_status"`
+	NXDomain    bool     `json:"nxdomain"`
+	Status      string   `json:"status"`
+	Vulnerable  bool     `json:"vulnerable"`
+
+	re *regexp.Regexp
+}
+
+// Confidence levels of a candidate.
+const (
+	ConfidenceHigh   = "high"
+	ConfidenceMedium = "medium"
+)
+
+// Load returns the fingerprints in path, or the built-in list when path is
+// empty. Services not marked vulnerable are dropped.
+func Load(path string) ([]Fingerprint, error) {
+	all := builtin
+	if path != "" {
+		f, err := os.Open(path)
+		if err != nil {
+			return nil, err
+		}
+		defer f.Close()
+		if all, err = Parse(f); err != nil {
+			return nil, fmt.Errorf("%s: %w", path, err)
+		}
+	}
+
+	var fps []Fingerprint
+	for _, fp := range all {
+		if !fp.Vulnerable || len(fp.CNAME) == 0 {
+			continue
+		}
+		if fp.Fingerprint != "" && fp.Fingerprint != "NXDOMAIN" {
+			re, err := regexp.Compile(fp.Fingerprint)
+			if err != nil {
+				re = regexp.MustCompile(regexp.QuoteMeta(fp.Fingerprint))
+			}
+			fp.re = re
+		}
+		fps = append(fps, fp)
+	}
+	return fps, nil
+}
+
+// Parse reads a fingerprints.json list.
+func Parse(r io.Reader) ([]Fingerprint, error) {
+	var fps []Fingerprint
+	if err := json.NewDecoder(r).Decode(&fps); err != nil {
+		return nil, fmt.Errorf("failed to parse fingerprints: %w", err)
+	}
+	if len(fps) == 0 {
+		return nil, fmt.Errorf("no fingerprints found")
+	}
+	return fps, nil
+}
+
+// Service returns the fingerprint whose CNAME suffixes match one of cnames,
+// and the matching CNAME.
+func Service(fps []Fingerprint, cnames []string) (*Fingerprint, string) {
+	for _, c := range cnames {
+		c = strings.TrimSuffix(strings.ToLower(c), ".")
+		for i := range fps {
+			for _, suffix := range fps[i].CNAME {
+				suffix = strings.TrimPrefix(strings.ToLower(suffix), ".")
+				if c == suffix || strings.HasSuffix(c, "."+suffix) {
+					return &fps[i], c
+				}
+			}
+		}
+	}
+	return nil, ""
+}
+
+// Check returns the evidence that d, whose CNAME points at fp's service, is
+// unclaimed, and the confidence, or "" when the response shows the resource
+// is in use. body may be nil when no body is stored.
+func Check(fp *Fingerprint, d *models.HTTPXData, body []byte) (evidence, confidence string) {
+	if fp.NXDomain || fp.re == nil {
+		// The CNAME target has to be dangling; a stored record without
+		// addresses is as close as the stored data gets.
+		if len(d.A) == 0 {
+			return "CNAME without A records", ConfidenceMedium
+		}
+		return "", ""
+	}
+	if fp.HTTPStatus != nil && *fp.HTTPStatus != d.StatusCode {
+		return "", ""
+	}
+	if m := fp.re.FindString(d.Title); m != "" {
+		return "title: " + m, ConfidenceHigh
+	}
+	if m := fp.re.Find(body); m != nil {
+		return "body: " + string(m), ConfidenceHigh
+	}
+	return "", ""
+}
@@ -0,0 +1,130 @@
+package technology
+
+import "strings"
+
+// categories maps lowercased Wappalyzer technology names, as reported by
+// httpx, to a coarse category.
+var categories = map[string]string{
+	"apache http server":   "web-server",
+	"apache":               "web-server",
+	"nginx":                "web-server",
+	"openresty":            "web-server",
+	"microsoft-iis":        "web-server",
+	"iis":                  "web-server",
+	"litespeed":            "web-server",
+	"caddy":                "web-server",
+	"envoy":                "web-server",
+	"apache tomcat":        "web-server",
+	"jetty":                "web-server",
+	"gunicorn":             "web-server",
+	"kestrel":              "web-server",
+	"php":                  "language",
+	"python":               "language",
+	"java":                 "language",
+	"ruby":                 "language",
+	"perl":                 "language",
+	"node.js":              "language",
+	"go":                   "language",
+	"microsoft asp.net":    "framework",
+	"express":              "framework",
+	"django":               "framework",
+	"flask":                "framework",
+	"laravel":              "framework",
+	"ruby on rails":        "framework",
+	"spring":               "framework",
+	"next.js":              "framework",
+	"nuxt.js":              "framework",
+	"angular":              "javascript-framework",
+	"angularjs":            "javascript-framework",
+	"react":                "javascript-framework",
+	"vue.js":               "javascript-framework",
+	"svelte":               "javascript-framework",
+	"jquery":               "javascript-library",
+	"jquery ui":            "javascript-library",
+	"lodash":               "javascript-library",
+	"moment.js":            "javascript-library",
+	"core-js":              "javascript-library",
+	"bootstrap":            "ui-framework",
+	"tailwind css":         "ui-framework",
+	"font awesome":         "font",
+	"google font api":      "font",
+	"wordpress":            "cms",
+	"drupal":               "cms",
+	"joomla":               "cms",
+	"ghost":                "cms",
+	"magento":              "ecommerce",
+	"shopify":              "ecommerce",
+	"woocommerce":          "ecommerce",
+	"cloudflare":           "cdn",
+	"akamai":               "cdn",
+	"fastly":               "cdn",
+	"amazon cloudfront":    "cdn",
+	"azure cdn":            "cdn",
+	"jsdelivr":             "cdn",
+	"unpkg":                "cdn",
+	"cdnjs":                "cdn",
+	"amazon web services":  "paas",
+	"amazon s3":            "paas",
+	"heroku":               "paas",
+	"vercel":               "paas",
+	"netlify":              "paas",
+	"github pages":         "paas",
+	"google cloud":         "paas",
+	"microsoft azure":      "paas",
+	"amazon elb":           "load-balancer",
+	"f5 bigip":             "load-balancer",
+	"varnish":              "cache",
+	"hsts":                 "security",
+	"recaptcha":            "security",
+	"hcaptcha":             "security",
+	"imperva":              "security",
+	"sucuri":               "security",
+	"google analytics":     "analytics",
+	"google tag manager":   "tag-manager",
+	"hotjar":               "analytics",
+	"jenkins":              "ci",
+	"gitlab":               "ci",
+	"grafana":              "monitoring",
+	"kibana":               "monitoring",
+	"elasticsearch":        "database",
+	"mysql":                "database",
+	"postgresql":           "database",
+	"mongodb":              "database",
+	"redis":                "database",
+	"ubuntu":               "operating-system",
+	"debian":               "operating-system",
+	"centos":               "operating-system",
+	"red hat":              "operating-system",
+	"windows server":       "operating-system",
+	"openssl":              "library",
+	"mod_ssl":              "web-server-extension",
+	"phusion passenger":    "web-server-extension",
+	"atlassian jira":       "issue-tracker",
+	"atlassian confluence": "wiki",
+	"keycloak":             "authentication",
+	"okta":                 "authentication",
+	"microsoft exchange":   "webmail",
+	"outlook web app":      "webmail",
+	"roundcube":            "webmail",
+	"phpmyadmin":           "database-manager",
+	"swagger ui":           "documentation",
+	"graphql":              "api",
+	"http/3":               "miscellaneous",
+	"open graph":           "miscellaneous",
+	"webpack":              "build-tool",
+	"vite":                 "build-tool",
+	"sentry":               "monitoring",
+	"new relic":            "monitoring",
+	"datadog":              "monitoring",
+	"docker":               "container",
+	"kubernetes":           "container",
+	"traefik":              "reverse-proxy",
+	"haproxy":              "reverse-proxy",
+	"squid":                "reverse-proxy",
+	"amazon api gateway":   "api",
+}
+
+// Category returns the category of a technology name, or "" when unknown.
+func Category(name string) string {
+	return categories[strings.ToLower(strings.TrimSpace(name))]
+}
@@ -0,0 +1,147 @@
+package technology
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// versionParts is the fixed length versions are padded to, so that "1.20"
+// and "1.20.0" compare equal in SQL array comparisons.
+const versionParts = 4
+
+// Tech is a single parsed httpx tech entry such as "nginx:1.18.0"
+type Tech struct {
+	Name     string `json:"name"`
+	Version  string `json:"version,omitempty"`
+	Category string `json:"category,omitempty"`
+}
+
+// Parse splits an httpx tech string into name and version and looks up its
+// category.
+func Parse(s string) Tech {
+	name, version, _ := strings.Cut(strings.TrimSpace(s), ":")
+	name = strings.TrimSpace(name)
+	return Tech{
+		Name:     name,
+		Version:  strings.TrimSpace(version),
+		Category: Category(name),
+	}
+}
+
+// VersionParts returns the numeric components of a version string padded to a
+// fixed length, or nil when the version has no leading number.
+// "7.4.3-ubuntu" becomes [7 4 3 0].
+func VersionParts(version string) []int32 {
+	version = strings.TrimPrefix(strings.TrimSpace(version), "v")
+	if version == "" {
+		return nil
+	}
+
+	parts := make([]int32, versionParts)
+	for i, field := range strings.SplitN(version, ".", versionParts+1) {
+		if i == versionParts {
+			break
+		}
+		digits := leadingDigits(field)
+		if digits == "" {
+			if i == 0 {
+				return nil
+			}
+			break
+		}
+		n, err := strconv.ParseInt(digits, 10, 32)
+		if err != nil {
+			return nil
+		}
+		parts[i] = int32(n)
+		if len(digits) != len(field) {
+			break
+		}
+	}
+	return parts
+}
+
+func leadingDigits(s string) string {
+	for i, r := range s {
+		if r < '0' || r > '9' {
+			return s[:i]
+		}
+	}
+	return s
+}
+
+// Constraint matches a technology name against one or more version bounds,
+// e.g. "PHP>=7,<8".
+type Constraint struct {
+	Name   string
+	Bounds []Bound
+}
+
+// Bound is a single comparison against a padded version
+type Bound struct {
+	Op      string
+	Version []int32
+}
+
+var operators = []string{">=", "<=", "!=", "==", ">", "<", "="}
+
+// HasConstraint reports whether s contains a version comparison operator.
+func HasConstraint(s string) bool {
+	return strings.ContainsAny(s, "<>=!")
+}
+
+// ParseConstraint parses expressions such as "nginx<1.20" or "PHP>=7,<8".
+func ParseConstraint(s string) (Constraint, error) {
+	idx := strings.IndexAny(s, "<>=!")
+	if idx <= 0 {
+		return Constraint{}, fmt.Errorf("invalid tech version constraint %q: expected <name><op><version>", s)
+	}
+
+	c := Constraint{Name: strings.TrimSpace(s[:idx])}
+	for _, expr := range strings.Split(s[idx:], ",") {
+		expr = strings.TrimSpace(expr)
+		op := ""
+		for _, o := range operators {
+			if strings.HasPrefix(expr, o) {
+				op = o
+				break
+			}
+		}
+		if op == "" {
+			return Constraint{}, fmt.Errorf("invalid tech version constraint %q: missing operator in %q", s, expr)
+		}
+		version := VersionParts(strings.TrimPrefix(expr, op))
+		if version == nil {
+			return Constraint{}, fmt.Errorf("invalid tech version constraint %q: bad version in %q", s, expr)
+		}
+		if op == "==" {
+			op = "="
+		}
+		if op == "!=" {
+			op = "<>"
+		}
+		c.Bounds = append(c.Bounds, Bound{Op: op, Version: version})
+	}
+	return c, nil
+}
+
+// CompareVersions compares two padded versions as returned by VersionParts,
+// returning -1, 0 or 1.
+func CompareVersions(a, b []int32) int {
+	for i := 0; i < len(a) && i < len(b); i++ {
+		if a[i] < b[i] {
+			return -1
+		}
+		if a[i] > b[i] {
+			return 1
+		}
+	}
+	switch {
+	case len(a) < len(b):
+		return -1
+	case len(a) > len(b):
+		return 1
+	}
+	return 0
+}
@@ -0,0 +1,78 @@
+package vuln
+
+import (
+	"math"
+	"strings"
+)
+
+var (
+	cvssAV = map[string]float64{"N": 0.85, "A": 0.62, "L": 0.55, "P": 0.2}
+	cvssAC = map[string]float64{"L": 0.77, "H": 0.44}
+	cvssUI = map[string]float64{"N": 0.85, "R": 0.62}
+	cvssCI = map[string]float64{"H": 0.56, "L": 0.22, "N": 0}
+)
+
+// CVSS3Score computes the CVSS v3.x base score of a vector such as
+// "CVSS:3.1/AV:N/AC:L/PR:N/UI:N/S:U/C:H/I:H/A:H". It returns 0 for vectors it
+// cannot parse.
+func CVSS3Score(vector string) float64 {
+	m := map[string]string{}
+	for _, part := range strings.Split(vector, "/") {
+		if k, v, ok := strings.Cut(part, ":"); ok {
+			m[k] = v
+		}
+	}
+
+	av, okAV := cvssAV[m["AV"]]
+	ac, okAC := cvssAC[m["AC"]]
+	ui, okUI := cvssUI[m["UI"]]
+	c, okC := cvssCI[m["C"]]
+	i, okI := cvssCI[m["I"]]
+	a, okA := cvssCI[m["A"]]
+	scope := m["S"]
+	if !okAV || !okAC || !okUI || !okC || !okI || !okA || (scope != "U" && scope != "C") {
+		return 0
+	}
+
+	var pr float64
+	switch m["PR"] {
+	case "N":
+		pr = 0.85
+	case "L":
+		pr = 0.62
+		if scope == "C" {
+			pr = 0.68
+		}
+	case "H":
+		pr = 0.27
+		if scope == "C" {
+			pr = 0.5
+		}
+	default:
+		return 0
+	}
+
+	iss := 1 - (1-c)*(1-i)*(1-a)
+	impact := 6.42 * iss
+	if scope == "C" {
+		impact = 7.52*(iss-0.029) - 3.25*math.Pow(iss-0.02, 15)
+	}
+	if impact <= 0 {
+		return 0
+	}
+
+	exploitability := 8.22 * av * ac * pr * ui
+	if scope == "C" {
+		return roundUp(math.Min(1.08*(impact+exploitability), 10))
+	}
+	return roundUp(math.Min(impact+exploitability, 10))
+}
+
+// roundUp implements the CVSS v3.1 Roundup function.
+func roundUp(x float64) float64 {
+	n := int64(math.Round(x * 100000))
+	if n%10000 == 0 {
+		return float64(n) / 100000
+	}
+	return float64(n/10000+1) / 10
+}
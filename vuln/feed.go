@@ -0,0 +1,333 @@
+package vuln
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+
+	"github.com/itsmeashim/rdb/technology"
+)
+
+// NVD JSON 2.0 (API responses and the 2.0 data feeds)
+type nvd2Item struct {
+	CVE struct {
+		ID      string `json:"id"`
+		Metrics struct {
+			V31 []nvd2Metric `json:"cvssMetricV31"`
+			V30 []nvd2Metric `json:"cvssMetricV30"`
+			V2  []nvd2Metric `json:"cvssMetricV2"`
+		} `json:"metrics"`
+		Configurations []struct {
+			Nodes []struct {
+				CPEMatch []nvdCPEMatch `json:"cpeMatch"`
+			} `json:"nodes"`
+		} `json:"configurations"`
+	} `json:"cve"`
+}
+
+type nvd2Metric struct {
+	CVSSData struct {
+		BaseScore float64 `json:"baseScore"`
+	} `json:"cvssData"`
+}
+
+// NVD JSON 1.1 legacy data feeds (nvdcve-1.1-*.json)
+type nvd1Item struct {
+	CVE struct {
+		Meta struct {
+			ID string `json:"ID"`
+		} `json:"CVE_data_meta"`
+	} `json:"cve"`
+	Configurations struct {
+		Nodes []nvd1Node `json:"nodes"`
+	} `json:"configurations"`
+	Impact struct {
+		V3 struct {
+			CVSS struct {
+				BaseScore float64 `json:"baseScore"`
+			} `json:"cvssV3"`
+		} `json:"baseMetricV3"`
+		V2 struct {
+			CVSS struct {
+				BaseScore float64 `json:"baseScore"`
+			} `json:"cvssV2"`
+		} `json:"baseMetricV2"`
+	} `json:"impact"`
+}
+
+type nvd1Node struct {
+	CPEMatch []nvdCPEMatch `json:"cpe_match"`
+	Children []nvd1Node    `json:"children"`
+}
+
+// nvdCPEMatch covers both the 2.0 ("criteria") and 1.1 ("cpe23Uri") layouts.
+type nvdCPEMatch struct {
+	Vulnerable            bool   `json:"vulnerable"`
+	Criteria              string `json:"criteria"`
+	CPE23URI              string `json:"cpe23Uri"`
+	VersionStartIncluding string `json:"versionStartIncluding"`
+	VersionStartExcluding string `json:"versionStartExcluding"`
+	VersionEndIncluding   string `json:"versionEndIncluding"`
+	VersionEndExcluding   string `json:"versionEndExcluding"`
+}
+
+// OSV schema (https://ossf.github.io/osv-schema/)
+type osvEntry struct {
+	ID       string   `json:"id"`
+	Aliases  []string `json:"aliases"`
+	Severity []struct {
+		Type  string `json:"type"`
+		Score string `json:"score"`
+	} `json:"severity"`
+	Affected []struct {
+		Package struct {
+			Name string `json:"name"`
+		} `json:"package"`
+		Ranges []struct {
+			Type   string `json:"type"`
+			Events []struct {
+				Introduced   string `json:"introduced"`
+				Fixed        string `json:"fixed"`
+				LastAffected string `json:"last_affected"`
+			} `json:"events"`
+		} `json:"ranges"`
+		Versions []string `json:"versions"`
+	} `json:"affected"`
+}
+
+type feedFile struct {
+	Vulnerabilities []nvd2Item `json:"vulnerabilities"`
+	CVEItems        []nvd1Item `json:"CVE_Items"`
+	osvEntry
+}
+
+// Parse reads an NVD 2.0, NVD 1.1 or OSV JSON dump and returns the affected
+// version ranges it describes. OSV dumps may be a single entry, an array of
+// entries or one entry per line.
+func Parse(r io.Reader, source string) ([]Range, error) {
+	br := bufio.NewReader(r)
+	first, err := firstByte(br)
+	if err != nil {
+		return nil, err
+	}
+
+	var ranges []Range
+	if first == '[' {
+		var entries []osvEntry
+		if err := json.NewDecoder(br).Decode(&entries); err != nil {
+			return nil, fmt.Errorf("failed to parse OSV array: %w", err)
+		}
+		for _, e := range entries {
+			ranges = append(ranges, e.ranges()...)
+		}
+		return withSource(ranges, source), nil
+	}
+
+	decoder := json.NewDecoder(br)
+	for {
+		var f feedFile
+		if err := decoder.Decode(&f); err == io.EOF {
+			break
+		} else if err != nil {
+			return nil, fmt.Errorf("failed to parse feed: %w", err)
+		}
+
+		for _, item := range f.Vulnerabilities {
+			ranges = append(ranges, item.ranges()...)
+		}
+		for _, item := range f.CVEItems {
+			ranges = append(ranges, item.ranges()...)
+		}
+		if f.ID != "" {
+			ranges = append(ranges, f.osvEntry.ranges()...)
+		}
+	}
+	return withSource(ranges, source), nil
+}
+
+// firstByte returns the first byte that is not whitespace or part of a UTF-8
+// byte order mark, leaving it unread.
+func firstByte(br *bufio.Reader) (byte, error) {
+	for {
+		b, err := br.ReadByte()
+		if err == io.EOF {
+			return 0, fmt.Errorf("feed is empty")
+		}
+		if err != nil {
+			return 0, err
+		}
+		switch b {
+		case ' ', '\t', '\r', '\n', 0xef, 0xbb, 0xbf:
+			continue
+		}
+		return b, br.UnreadByte()
+	}
+}
+
+func withSource(ranges []Range, source string) []Range {
+	for i := range ranges {
+		ranges[i].Source = source
+	}
+	return ranges
+}
+
+func (item nvd2Item) ranges() []Range {
+	score := 0.0
+	for _, metrics := range [][]nvd2Metric{item.CVE.Metrics.V31, item.CVE.Metrics.V30, item.CVE.Metrics.V2} {
+		if len(metrics) > 0 {
+			score = metrics[0].CVSSData.BaseScore
+			break
+		}
+	}
+
+	var ranges []Range
+	for _, cfg := range item.CVE.Configurations {
+		for _, node := range cfg.Nodes {
+			for _, m := range node.CPEMatch {
+				if r, ok := m.toRange(item.CVE.ID, score); ok {
+					ranges = append(ranges, r)
+				}
+			}
+		}
+	}
+	return ranges
+}
+
+func (item nvd1Item) ranges() []Range {
+	score := item.Impact.V3.CVSS.BaseScore
+	if score == 0 {
+		score = item.Impact.V2.CVSS.BaseScore
+	}
+
+	var ranges []Range
+	var walk func(nodes []nvd1Node)
+	walk = func(nodes []nvd1Node) {
+		for _, node := range nodes {
+			for _, m := range node.CPEMatch {
+				if r, ok := m.toRange(item.CVE.Meta.ID, score); ok {
+					ranges = append(ranges, r)
+				}
+			}
+			walk(node.Children)
+		}
+	}
+	walk(item.Configurations.Nodes)
+	return ranges
+}
+
+// toRange converts a vulnerable application CPE match into a Range. Matches
+// without any version information are skipped since they cannot be compared.
+func (m nvdCPEMatch) toRange(cve string, score float64) (Range, bool) {
+	uri := m.Criteria
+	if uri == "" {
+		uri = m.CPE23URI
+	}
+	fields := strings.Split(uri, ":")
+	if !m.Vulnerable || len(fields) < 6 || fields[2] != "a" {
+		return Range{}, false
+	}
+
+	r := Range{CVE: cve, Vendor: fields[3], Product: fields[4], CVSS: score}
+	if v := fields[5]; v != "*" && v != "-" {
+		r.Exact = technology.VersionParts(v)
+		return r, r.Exact != nil
+	}
+
+	switch {
+	case m.VersionStartIncluding != "":
+		r.Start, r.StartIncl = technology.VersionParts(m.VersionStartIncluding), true
+	case m.VersionStartExcluding != "":
+		r.Start = technology.VersionParts(m.VersionStartExcluding)
+	}
+	switch {
+	case m.VersionEndIncluding != "":
+		r.End, r.EndIncl = technology.VersionParts(m.VersionEndIncluding), true
+	case m.VersionEndExcluding != "":
+		r.End = technology.VersionParts(m.VersionEndExcluding)
+	}
+	return r, r.Start != nil || r.End != nil
+}
+
+func (e osvEntry) ranges() []Range {
+	cve := e.ID
+	for _, alias := range e.Aliases {
+		if strings.HasPrefix(alias, "CVE-") {
+			cve = alias
+			break
+		}
+	}
+
+	score := 0.0
+	for _, s := range e.Severity {
+		if f, err := strconv.ParseFloat(s.Score, 64); err == nil {
+			score = f
+			break
+		}
+		if strings.HasPrefix(s.Type, "CVSS_V3") {
+			score = CVSS3Score(s.Score)
+			break
+		}
+	}
+
+	var ranges []Range
+	for _, a := range e.Affected {
+		name := strings.ToLower(a.Package.Name)
+		if name == "" {
+			continue
+		}
+		// Ecosystem packages are often "vendor/product" or "group:artifact".
+		if i := strings.LastIndexAny(name, "/:"); i >= 0 {
+			name = name[i+1:]
+		}
+		name = strings.ReplaceAll(name, " ", "_")
+
+		for _, rng := range a.Ranges {
+			// GIT ranges are bounded by commit hashes, not versions.
+			if rng.Type == "GIT" {
+				continue
+			}
+			// A bound that is not a version would make the range match
+			// every version, so such ranges are dropped.
+			var current *Range
+			valid := false
+			for _, ev := range rng.Events {
+				switch {
+				case ev.Introduced != "":
+					current = &Range{CVE: cve, Product: name, CVSS: score, StartIncl: true}
+					valid = true
+					if ev.Introduced != "0" {
+						current.Start = technology.VersionParts(ev.Introduced)
+						valid = current.Start != nil
+					}
+				case ev.Fixed != "" && current != nil:
+					current.End = technology.VersionParts(ev.Fixed)
+					if valid && current.End != nil {
+						ranges = append(ranges, *current)
+					}
+					current = nil
+				case ev.LastAffected != "" && current != nil:
+					current.End, current.EndIncl = technology.VersionParts(ev.LastAffected), true
+					if valid && current.End != nil {
+						ranges = append(ranges, *current)
+					}
+					current = nil
+				}
+			}
+			if current != nil && valid {
+				ranges = append(ranges, *current)
+			}
+		}
+
+		if len(a.Ranges) == 0 {
+			for _, v := range a.Versions {
+				if parts := technology.VersionParts(v); parts != nil {
+					ranges = append(ranges, Range{CVE: cve, Product: name, CVSS: score, Exact: parts})
+				}
+			}
+		}
+	}
+	return ranges
+}
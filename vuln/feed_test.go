@@ -0,0 +1,95 @@
+package vuln
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+
+	"github.com/itsmeashim/rdb/technology"
+)
+
+func TestOSVRanges(t *testing.T) {
+	v := technology.VersionParts
+	tests := []struct {
+		name     string
+		affected string
+		want     []Range
+	}{
+		{
+			name:     "semver introduced and fixed",
+			affected: `{"package":{"name":"nginx"},"ranges":[{"type":"SEMVER","events":[{"introduced":"1.2.0"},{"fixed":"1.2.5"}]}]}`,
+			want:     []Range{{Product: "nginx", Start: v("1.2.0"), StartIncl: true, End: v("1.2.5")}},
+		},
+		{
+			name:     "introduced 0 and fixed",
+			affected: `{"package":{"name":"nginx"},"ranges":[{"type":"ECOSYSTEM","events":[{"introduced":"0"},{"fixed":"1.2.5"}]}]}`,
+			want:     []Range{{Product: "nginx", StartIncl: true, End: v("1.2.5")}},
+		},
+		{
+			name:     "last affected is inclusive",
+			affected: `{"package":{"name":"nginx"},"ranges":[{"type":"SEMVER","events":[{"introduced":"1.0"},{"last_affected":"1.4"}]}]}`,
+			want:     []Range{{Product: "nginx", Start: v("1.0"), StartIncl: true, End: v("1.4"), EndIncl: true}},
+		},
+		{
+			name:     "introduced 0 without end affects every version",
+			affected: `{"package":{"name":"nginx"},"ranges":[{"type":"ECOSYSTEM","events":[{"introduced":"0"}]}]}`,
+			want:     []Range{{Product: "nginx", StartIncl: true}},
+		},
+		{
+			name:     "several ranges in one event list",
+			affected: `{"package":{"name":"nginx"},"ranges":[{"type":"SEMVER","events":[{"introduced":"1.0"},{"fixed":"1.1"},{"introduced":"2.0"},{"fixed":"2.3"}]}]}`,
+			want: []Range{
+				{Product: "nginx", Start: v("1.0"), StartIncl: true, End: v("1.1")},
+				{Product: "nginx", Start: v("2.0"), StartIncl: true, End: v("2.3")},
+			},
+		},
+		{
+			name:     "git ranges are skipped",
+			affected: `{"package":{"name":"nginx"},"ranges":[{"type":"GIT","events":[{"introduced":"0"},{"fixed":"9f86d081884c7d659a2feaa0c55ad015a3bf4f1b"}]}]}`,
+		},
+		{
+			name:     "fixed that is not a version",
+			affected: `{"package":{"name":"nginx"},"ranges":[{"type":"ECOSYSTEM","events":[{"introduced":"0"},{"fixed":"abcdef"}]}]}`,
+		},
+		{
+			name:     "introduced that is not a version",
+			affected: `{"package":{"name":"nginx"},"ranges":[{"type":"ECOSYSTEM","events":[{"introduced":"abcdef"},{"fixed":"1.2"}]}]}`,
+		},
+		{
+			name:     "open range from an introduced that is not a version",
+			affected: `{"package":{"name":"nginx"},"ranges":[{"type":"ECOSYSTEM","events":[{"introduced":"abcdef"}]}]}`,
+		},
+		{
+			name:     "package name without ecosystem prefix",
+			affected: `{"package":{"name":"github.com/gin-gonic/gin"},"ranges":[{"type":"SEMVER","events":[{"introduced":"0"},{"fixed":"1.9.1"}]}]}`,
+			want:     []Range{{Product: "gin", StartIncl: true, End: v("1.9.1")}},
+		},
+		{
+			name:     "exact versions without ranges",
+			affected: `{"package":{"name":"nginx"},"versions":["1.2.3","not-a-version"]}`,
+			want:     []Range{{Product: "nginx", Exact: v("1.2.3")}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var e osvEntry
+			doc := `{"id":"GHSA-test","aliases":["CVE-2024-0001"],"severity":[{"type":"CVSS_V3","score":"7.5"}],"affected":[` + tt.affected + `]}`
+			if err := json.Unmarshal([]byte(doc), &e); err != nil {
+				t.Fatalf("unmarshal: %v", err)
+			}
+			for i := range tt.want {
+				tt.want[i].CVE = "CVE-2024-0001"
+				tt.want[i].CVSS = 7.5
+			}
+
+			got := e.ranges()
+			if len(got) == 0 && len(tt.want) == 0 {
+				return
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("ranges() =\n%+v\nwant\n%+v", got, tt.want)
+			}
+		})
+	}
+}
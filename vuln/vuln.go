@@ -0,0 +1,101 @@
+package vuln
+
+import (
+	"strings"
+
+	"github.com/itsmeashim/rdb/technology"
+)
+
+// Range is one affected product version range of a CVE. Exact pins a single
+// version; otherwise Start and End bound the range and a nil bound is open.
+type Range struct {
+	CVE       string
+	Vendor    string
+	Product   string
+	Exact     []int32
+	Start     []int32
+	StartIncl bool
+	End       []int32
+	EndIncl   bool
+	CVSS      float64
+	Source    string
+}
+
+// Contains reports whether a padded version falls inside the range.
+func (r Range) Contains(version []int32) bool {
+	if version == nil {
+		return false
+	}
+	if r.Exact != nil {
+		return technology.CompareVersions(version, r.Exact) == 0
+	}
+	if r.Start != nil {
+		c := technology.CompareVersions(version, r.Start)
+		if c < 0 || (c == 0 && !r.StartIncl) {
+			return false
+		}
+	}
+	if r.End != nil {
+		c := technology.CompareVersions(version, r.End)
+		if c > 0 || (c == 0 && !r.EndIncl) {
+			return false
+		}
+	}
+	return true
+}
+
+type product struct {
+	vendor string
+	name   string
+}
+
+// aliases maps httpx technology names to CPE vendor/product pairs where the
+// normalized name differs from the CPE product.
+var aliases = map[string]product{
+	"apache http server":   {"apache", "http_server"},
+	"apache":               {"apache", "http_server"},
+	"apache tomcat":        {"apache", "tomcat"},
+	"microsoft-iis":        {"microsoft", "internet_information_services"},
+	"iis":                  {"microsoft", "internet_information_services"},
+	"microsoft asp.net":    {"microsoft", "asp.net"},
+	"node.js":              {"nodejs", "node.js"},
+	"jquery ui":            {"jquery", "jquery_ui"},
+	"ruby on rails":        {"rubyonrails", "rails"},
+	"atlassian jira":       {"atlassian", "jira"},
+	"atlassian confluence": {"atlassian", "confluence"},
+	"openresty":            {"openresty", "openresty"},
+	"litespeed":            {"litespeedtech", "litespeed_web_server"},
+	"phpmyadmin":           {"phpmyadmin", "phpmyadmin"},
+	"f5 bigip":             {"f5", "big-ip_local_traffic_manager"},
+}
+
+// Product returns the CPE vendor (empty when the technology has no alias)
+// and product name a technology is matched against.
+func Product(techName string) (vendor, name string) {
+	key := strings.ToLower(strings.TrimSpace(techName))
+	if p, ok := aliases[key]; ok {
+		return p.vendor, p.name
+	}
+	return "", strings.ReplaceAll(key, " ", "_")
+}
+
+// Match confidences. Without a vendor on both sides only the product name
+// is compared, and short generic names ("go", "express") are shared by
+// unrelated products of other vendors.
+const (
+	ConfidenceHigh = "high"
+	ConfidenceLow  = "low"
+)
+
+// VendorMatch reports whether r can affect a technology of the given CPE
+// vendor, and how confident that is: high when both vendors are known and
+// equal, low when either is unknown.
+func (r Range) VendorMatch(vendor string) (confidence string, ok bool) {
+	if vendor == "" || r.Vendor == "" {
+		return ConfidenceLow, true
+	}
+	if r.Vendor != vendor {
+		return "", false
+	}
+	return ConfidenceHigh, true
+}
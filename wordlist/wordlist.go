@@ -0,0 +1,116 @@
+package wordlist
+
+import (
+	"net/url"
+	"regexp"
+	"sort"
+	"strings"
+	"unicode"
+)
+
+// Kinds are the supported wordlist kinds.
+var Kinds = []string{"subdomains", "paths", "params", "title-words"}
+
+// Entry is a word and the number of distinct sources it was found in
+type Entry struct {
+	Word  string `json:"word"`
+	Count int    `json:"count"`
+}
+
+// noise matches path segments that are identifiers rather than words:
+// numbers, UUIDs and long hex strings.
+var noise = regexp.MustCompile(`^(\d+|[0-9a-f]{8}-[0-9a-f]{4}-[0-9a-f]{4}-[0-9a-f]{4}-[0-9a-f]{12}|[0-9a-f]{16,})$`)
+
+// stopwords are common title words that make poor wordlist entries.
+var stopwords = map[string]bool{
+	"a": true, "an": true, "and": true, "are": true, "as": true, "at": true, "be": true, "by": true,
+	"for": true, "from": true, "in": true, "is": true, "it": true, "of": true, "on": true, "or": true,
+	"the": true, "this": true, "to": true, "with": true, "your": true, "you": true, "page": true,
+	"welcome": true, "home": true,
+}
+
+// Words returns the distinct words of kind found in one source value: a
+// subdomain for subdomains, a URL for paths and params, and a page title
+// for title-words.
+func Words(kind, value string) []string {
+	var words []string
+	switch kind {
+	case "subdomains":
+		words = strings.Split(strings.ToLower(value), ".")
+	case "paths":
+		u, err := url.Parse(value)
+		if err != nil {
+			return nil
+		}
+		for _, seg := range strings.Split(u.Path, "/") {
+			if seg != "" && !noise.MatchString(strings.ToLower(seg)) {
+				words = append(words, seg)
+			}
+		}
+	case "params":
+		u, err := url.Parse(value)
+		if err != nil {
+			return nil
+		}
+		for name := range u.Query() {
+			words = append(words, name)
+		}
+	case "title-words":
+		for _, w := range strings.FieldsFunc(strings.ToLower(value), func(r rune) bool {
+			return !unicode.IsLetter(r) && !unicode.IsDigit(r) && r != '-' && r != '_'
+		}) {
+			w = strings.Trim(w, "-_")
+			if w != "" && !stopwords[w] && !noise.MatchString(w) {
+				words = append(words, w)
+			}
+		}
+	}
+
+	seen := map[string]bool{}
+	distinct := words[:0]
+	for _, w := range words {
+		if w != "" && !seen[w] {
+			seen[w] = true
+			distinct = append(distinct, w)
+		}
+	}
+	return distinct
+}
+
+// Options filters Rank.
+type Options struct {
+	MinCount int
+	MinLen   int
+	MaxLen   int
+	Limit    int
+}
+
+// Rank counts the words of kind across values and returns them by
+// descending frequency, then alphabetically.
+func Rank(kind string, values []string, opts Options) []Entry {
+	counts := map[string]int{}
+	for _, v := range values {
+		for _, w := range Words(kind, v) {
+			counts[w]++
+		}
+	}
+
+	var entries []Entry
+	for w, n := range counts {
+		length := len([]rune(w))
+		if n < opts.MinCount || length < opts.MinLen || (opts.MaxLen > 0 && length > opts.MaxLen) {
+			continue
+		}
+		entries = append(entries, Entry{Word: w, Count: n})
+	}
+	sort.Slice(entries, func(i, j int) bool {
+		if entries[i].Count != entries[j].Count {
+			return entries[i].Count > entries[j].Count
+		}
+		return entries[i].Word < entries[j].Word
+	})
+	if opts.Limit > 0 && len(entries) > opts.Limit {
+		entries = entries[:opts.Limit]
+	}
+	return entries
+}